@@ -44,6 +44,10 @@ func (q AuthLogin) ValidateProtocol(response interface{}) error {
 	}
 }
 
+func (q AuthLogin) Idempotent() bool {
+	return true
+}
+
 // https://docs.skytable.io/actions/auth#logout
 type AuthLogout struct{}
 
@@ -76,6 +80,10 @@ func (q AuthLogout) ValidateProtocol(response interface{}) error {
 	}
 }
 
+func (q AuthLogout) Idempotent() bool {
+	return true
+}
+
 // https://docs.skytable.io/actions/auth#claim
 type AuthClaim struct {
 	OriginKey string
@@ -110,6 +118,10 @@ func (q AuthClaim) ValidateProtocol(response interface{}) error {
 	}
 }
 
+func (q AuthClaim) Idempotent() bool {
+	return false
+}
+
 // https://docs.skytable.io/actions/auth#adduser
 type AuthAddUser struct {
 	Username string
@@ -144,6 +156,10 @@ func (q AuthAddUser) ValidateProtocol(response interface{}) error {
 	}
 }
 
+func (q AuthAddUser) Idempotent() bool {
+	return false
+}
+
 // https://docs.skytable.io/actions/auth#deluser
 type AuthDelUser struct {
 	Username string
@@ -178,6 +194,11 @@ func (q AuthDelUser) ValidateProtocol(response interface{}) error {
 	}
 }
 
+// A repeated AUTH DELUSER fails once the first one deleted the user, so it's not retried.
+func (q AuthDelUser) Idempotent() bool {
+	return false
+}
+
 // https://docs.skytable.io/actions/auth#restore
 type AuthRestore struct {
 	OriginKey string // If "", omitted in the sent command
@@ -229,6 +250,10 @@ func (q AuthRestore) ValidateProtocol(response interface{}) error {
 	}
 }
 
+func (q AuthRestore) Idempotent() bool {
+	return false
+}
+
 // https://docs.skytable.io/actions/auth#listuser
 type AuthListUser struct{}
 
@@ -254,6 +279,10 @@ func (q AuthListUser) ValidateProtocol(response interface{}) error {
 	}
 }
 
+func (q AuthListUser) Idempotent() bool {
+	return true
+}
+
 // https://docs.skytable.io/actions/auth#whoami
 type AuthWhoAmI struct{}
 
@@ -263,7 +292,7 @@ func (q AuthWhoAmI) AppendToPacket(builder *strings.Builder) error {
 		return err
 	}
 
-	err = AppendElements(builder, false, "AUTH", "LISTUSER")
+	err = AppendElements(builder, false, "AUTH", "WHOAMI")
 	if err != nil {
 		return err
 	}
@@ -277,4 +306,8 @@ func (q AuthWhoAmI) ValidateProtocol(response interface{}) error {
 	default:
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("AUTH WHOAMI: Unexpected response element: %v", response), nil)
 	}
-}
\ No newline at end of file
+}
+
+func (q AuthWhoAmI) Idempotent() bool {
+	return true
+}
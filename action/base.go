@@ -2,7 +2,6 @@ package action
 
 import (
 	"fmt"
-	"math"
 	"strconv"
 	"strings"
 
@@ -25,6 +24,15 @@ func AppendElements(builder *strings.Builder, typed bool, v ...any) error {
 	return nil
 }
 
+// AppendElement appends v to the packet.
+//
+// Values of types not natively supported are converted first, in the order of:
+//   - Encoders registered with [RegisterEncoder]
+//   - encoding.TextMarshaler, as a string
+//   - encoding.BinaryMarshaler, as a binary string
+//   - json.Marshaler, as a string
+//   - fmt.Stringer, as a string, if enabled by [EnableStringerEncoding]
+//   - bool, float64 and named/smaller numeric, string and []byte types, by their underlying kind
 func AppendElement(builder *strings.Builder, typed bool, v interface{}) error {
 	if v == nil {
 		fmt.Fprintf(builder, "\\0\n")
@@ -33,96 +41,31 @@ func AppendElement(builder *strings.Builder, typed bool, v interface{}) error {
 
 	switch v := v.(type) {
 	case string:
-		if typed {
-			fmt.Fprintf(builder, "+%d\n%s\n", len(v), v)
-		} else {
-			fmt.Fprintf(builder, "%d\n%s\n", len(v), v)
-		}
+		appendSized(builder, typed, protocol.DataTypeString, v)
 	case int8:
-		if typed {
-			if v >= 100 || v <= -100 {
-				fmt.Fprintf(builder, "-3\n%d\n", v)
-			} else if v >= 10 || v <= -10 {
-				fmt.Fprintf(builder, "-2\n%d\n", v)
-			} else {
-				fmt.Fprintf(builder, "-1\n%d\n", v)
-			}
-		} else {
-			if v >= 100 || v <= -100 {
-				fmt.Fprintf(builder, "3\n%d\n", v)
-			} else if v >= 10 || v <= -10 {
-				fmt.Fprintf(builder, "2\n%d\n", v)
-			} else {
-				fmt.Fprintf(builder, "1\n%d\n", v)
-			}
-		}
+		appendSized(builder, typed, protocol.DataTypeSmallintSigned, strconv.FormatInt(int64(v), 10))
 	case uint8:
-		if typed {
-			if v >= 100 {
-				fmt.Fprintf(builder, ".3\n%d\n", v)
-			} else if v >= 10 {
-				fmt.Fprintf(builder, ".2\n%d\n", v)
-			} else {
-				fmt.Fprintf(builder, ".1\n%d\n", v)
-			}
-		} else {
-			if v >= 100 {
-				fmt.Fprintf(builder, "3\n%d\n", v)
-			} else if v >= 10 {
-				fmt.Fprintf(builder, "2\n%d\n", v)
-			} else {
-				fmt.Fprintf(builder, "1\n%d\n", v)
-			}
-		}
+		appendSized(builder, typed, protocol.DataTypeSmallint, strconv.FormatUint(uint64(v), 10))
 	case int: // as int64
-		if typed {
-			fmt.Fprintf(builder, ";%d\n%d\n", int64(math.Log10(float64(v)))+1, v)
-		} else {
-			fmt.Fprintf(builder, "%d\n%d\n", int64(math.Log10(float64(v)))+1, v)
-		}
+		appendSized(builder, typed, protocol.DataTypeIntSigned, strconv.FormatInt(int64(v), 10))
 	case int32: // as int64
-		if typed {
-			fmt.Fprintf(builder, ";%d\n%d\n", int64(math.Log10(float64(v)))+1, v)
-		} else {
-			fmt.Fprintf(builder, "%d\n%d\n", int64(math.Log10(float64(v)))+1, v)
-		}
+		appendSized(builder, typed, protocol.DataTypeIntSigned, strconv.FormatInt(int64(v), 10))
 	case int64:
-		if typed {
-			fmt.Fprintf(builder, ";%d\n%d\n", int64(math.Log10(float64(v)))+1, v)
-		} else {
-			fmt.Fprintf(builder, "%d\n%d\n", int64(math.Log10(float64(v)))+1, v)
-		}
+		appendSized(builder, typed, protocol.DataTypeIntSigned, strconv.FormatInt(v, 10))
 	case uint: // as uint64
-		if typed {
-			fmt.Fprintf(builder, ":%d\n%d\n", uint64(math.Log10(float64(v)))+1, v)
-		} else {
-			fmt.Fprintf(builder, "%d\n%d\n", uint64(math.Log10(float64(v)))+1, v)
-		}
+		appendSized(builder, typed, protocol.DataTypeInt, strconv.FormatUint(uint64(v), 10))
 	case uint32: // as uint64
-		if typed {
-			fmt.Fprintf(builder, ":%d\n%d\n", uint64(math.Log10(float64(v)))+1, v)
-		} else {
-			fmt.Fprintf(builder, "%d\n%d\n", uint64(math.Log10(float64(v)))+1, v)
-		}
+		appendSized(builder, typed, protocol.DataTypeInt, strconv.FormatUint(uint64(v), 10))
 	case uint64:
-		if typed {
-			fmt.Fprintf(builder, ":%d\n%d\n", uint64(math.Log10(float64(v)))+1, v)
-		} else {
-			fmt.Fprintf(builder, "%d\n%d\n", uint64(math.Log10(float64(v)))+1, v)
-		}
+		appendSized(builder, typed, protocol.DataTypeInt, strconv.FormatUint(v, 10))
 	case float32:
-		if typed {
-			fmt.Fprintf(builder, "%%%d\n%f\n", len(strconv.FormatFloat(float64(v), 'f', -1, 32)), v)
-		} else {
-			fmt.Fprintf(builder, "%d\n%f\n", len(strconv.FormatFloat(float64(v), 'f', -1, 32)), v)
-		}
+		appendSized(builder, typed, protocol.DataTypeFloat, strconv.FormatFloat(float64(v), 'f', -1, 32))
 	case []byte:
-		// ???
 		if typed {
-			fmt.Fprintf(builder, "?%d\n", len(string(v)))
-		} else {
-			fmt.Fprintf(builder, "%d\n", len(string(v)))
+			builder.WriteByte(byte(protocol.DataTypeBinaryString))
 		}
+		builder.WriteString(strconv.Itoa(len(v)))
+		builder.WriteByte('\n')
 		builder.Write(v)
 		builder.WriteByte('\n')
 	case *protocol.TypedArray:
@@ -210,12 +153,27 @@ func AppendElement(builder *strings.Builder, typed bool, v interface{}) error {
 			return protocol.ErrIncorrectArrayUsage
 		}
 	default:
-		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("Appending an unexpected element: %v (%T)", v, v), nil)
+		encoded, err := encodeExtended(v)
+		if err != nil {
+			return err
+		}
+		return AppendElement(builder, typed, encoded)
 	}
 
 	return nil
 }
 
+// appendSized writes the formatted value with its length, so the length always equals the bytes written.
+func appendSized(builder *strings.Builder, typed bool, dt protocol.DataType, formatted string) {
+	if typed {
+		builder.WriteByte(byte(dt))
+	}
+	builder.WriteString(strconv.Itoa(len(formatted)))
+	builder.WriteByte('\n')
+	builder.WriteString(formatted)
+	builder.WriteByte('\n')
+}
+
 // elementType is only used when it's a TypedArray or TypedNonNullArray
 func AppendArrayHeader(arrayType protocol.CompoundType, elementType protocol.DataType, elementCount int, builder *strings.Builder) error {
 	switch arrayType {
@@ -0,0 +1,111 @@
+package action
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/No3371/go-skytable/protocol"
+)
+
+// parseSpecElement parses a single element as specified by Skyhash 1.1:
+//
+//	[tsymbol]<length>\n<payload>\n
+func parseSpecElement(raw string, typed bool) (dt protocol.DataType, payload string, err error) {
+	if typed {
+		if len(raw) == 0 {
+			return 0, "", errors.New("missing tsymbol")
+		}
+		dt, raw = protocol.DataType(raw[0]), raw[1:]
+	}
+
+	lf := strings.IndexByte(raw, '\n')
+	if lf < 1 {
+		return 0, "", errors.New("missing length")
+	}
+
+	length, err := strconv.Atoi(raw[:lf])
+	if err != nil || length < 0 {
+		return 0, "", errors.New("invalid length: " + raw[:lf])
+	}
+
+	raw = raw[lf+1:]
+	if len(raw) != length+1 || raw[length] != '\n' {
+		return 0, "", errors.New("length does not match the payload: " + strconv.Itoa(length))
+	}
+
+	return dt, raw[:length], nil
+}
+
+func TestAppendElement_Length(t *testing.T) {
+	tests := []struct {
+		name   string
+		v      any
+		wantDt protocol.DataType
+		want   string
+	}{
+		{"int zero", 0, protocol.DataTypeIntSigned, "0"},
+		{"int negative", -1234, protocol.DataTypeIntSigned, "-1234"},
+		{"int32 negative", int32(-5), protocol.DataTypeIntSigned, "-5"},
+		{"int64 min", int64(-9223372036854775808), protocol.DataTypeIntSigned, "-9223372036854775808"},
+		{"uint zero", uint(0), protocol.DataTypeInt, "0"},
+		{"uint32", uint32(1000), protocol.DataTypeInt, "1000"},
+		{"uint64 max", uint64(18446744073709551615), protocol.DataTypeInt, "18446744073709551615"},
+		{"int8 min", int8(-128), protocol.DataTypeSmallintSigned, "-128"},
+		{"uint8 zero", uint8(0), protocol.DataTypeSmallint, "0"},
+		{"float32", float32(1.25), protocol.DataTypeFloat, "1.25"},
+		{"float32 negative", float32(-0.1), protocol.DataTypeFloat, "-0.1"},
+		{"string empty", "", protocol.DataTypeString, ""},
+		{"bytes", []byte{0, '\n', 2}, protocol.DataTypeBinaryString, "\x00\n\x02"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, typed := range []bool{false, true} {
+				b := &strings.Builder{}
+				if err := AppendElement(b, typed, tt.v); err != nil {
+					t.Fatal(err)
+				}
+
+				dt, payload, err := parseSpecElement(b.String(), typed)
+				if err != nil {
+					t.Fatalf("AppendElement() = %q: %v", b.String(), err)
+				}
+				if typed && dt != tt.wantDt {
+					t.Errorf("AppendElement() tsymbol = %c, want %c", dt, tt.wantDt)
+				}
+				if payload != tt.want {
+					t.Errorf("AppendElement() payload = %q, want %q", payload, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func FuzzAppendElement(f *testing.F) {
+	f.Add(int64(0), uint64(0), float32(0), "", []byte{})
+	f.Add(int64(-1), uint64(10), float32(-1.5), "SET", []byte("\n"))
+	f.Add(int64(-9223372036854775808), uint64(18446744073709551615), float32(3.4e38), "あ得", []byte{0xff})
+
+	f.Fuzz(func(t *testing.T, i int64, u uint64, fl float32, s string, bs []byte) {
+		values := []any{i, int(i), int32(i), int8(i), u, uint(u), uint32(u), uint8(u), fl, s, bs}
+		for _, v := range values {
+			for _, typed := range []bool{false, true} {
+				b := &strings.Builder{}
+				if err := AppendElement(b, typed, v); err != nil {
+					t.Fatalf("AppendElement(%T) error: %v", v, err)
+				}
+
+				_, payload, err := parseSpecElement(b.String(), typed)
+				if err != nil {
+					t.Fatalf("AppendElement(%T) = %q: %v", v, b.String(), err)
+				}
+
+				if bs, isBytes := v.([]byte); isBytes && !bytes.Equal([]byte(payload), bs) {
+					t.Fatalf("AppendElement([]byte) payload = %q, want %q", payload, bs)
+				}
+			}
+		}
+	})
+}
@@ -53,3 +53,8 @@ func (q CreateKeyspace) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("CreateKeyspace: Unexpected response element: %v", response), nil)
 	}
 }
+
+// A repeated CREATE KEYSPACE fails with already-exists although the first one may have created it, so it's not retried.
+func (q CreateKeyspace) Idempotent() bool {
+	return false
+}
@@ -16,13 +16,15 @@ type CreateTable struct {
 
 func FormatSingleCreateTablePacket(path string, modelDesc any) (string, error) {
 	switch modelDesc := modelDesc.(type) {
-	case protocol.KeyMapDescription:
+	case protocol.ModelDescription:
 		m := modelDesc.Model()
-		if modelDesc.Volatile {
-			return fmt.Sprintf("*1\n~5\n6\nCREATE\n%d\n%s\n%d\n%s\n8\nvolatile\n", len(path), path, len(m), m), nil
-		} else {
-			return fmt.Sprintf("*1\n~4\n6\nCREATE\n%d\n%s\n%d\n%s\n", len(path), path, len(m), m), nil
+		props := strings.Fields(modelDesc.Properties())
+		var b strings.Builder
+		fmt.Fprintf(&b, "*1\n~%d\n6\nCREATE\n5\nTABLE\n%d\n%s\n%d\n%s\n", 4+len(props), len(path), path, len(m), m)
+		for _, p := range props {
+			fmt.Fprintf(&b, "%d\n%s\n", len(p), p)
 		}
+		return b.String(), nil
 	default:
 		return "", errors.New("unexpected model description")
 	}
@@ -34,13 +36,9 @@ func (q CreateTable) AppendToPacket(builder *strings.Builder) (err error) {
 	}
 
 	switch modelDesc := q.ModelDescription.(type) {
-	case protocol.KeyMapDescription:
-		if modelDesc.Volatile {
-			err = AppendArrayHeader(protocol.CompoundTypeAnyArray, 0, 5, builder)
-		} else {
-			err = AppendArrayHeader(protocol.CompoundTypeAnyArray, 0, 4, builder)
-		}
-
+	case protocol.ModelDescription:
+		props := strings.Fields(modelDesc.Properties())
+		err = AppendArrayHeader(protocol.CompoundTypeAnyArray, 0, 4+len(props), builder)
 		if err != nil {
 			return err
 		}
@@ -50,8 +48,8 @@ func (q CreateTable) AppendToPacket(builder *strings.Builder) (err error) {
 			return err
 		}
 
-		if modelDesc.Volatile {
-			err = AppendElement(builder, false, "volatile")
+		for _, p := range props {
+			err = AppendElement(builder, false, p)
 			if err != nil {
 				return err
 			}
@@ -80,3 +78,8 @@ func (q CreateTable) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("CreateTable: Unexpected response element: %v", response), nil)
 	}
 }
+
+// A repeated CREATE TABLE fails with already-exists although the first one may have created it, so it's not retried.
+func (q CreateTable) Idempotent() bool {
+	return false
+}
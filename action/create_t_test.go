@@ -0,0 +1,32 @@
+package action
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/No3371/go-skytable/protocol"
+)
+
+func TestFormatSingleCreateTablePacket(t *testing.T) {
+	for _, desc := range []protocol.ModelDescription{
+		protocol.KeyMapDescription{KeyType: protocol.DDLDataTypes_String, ValueType: protocol.DDLDataTypes_BinaryString},
+		protocol.KeyMapDescription{KeyType: protocol.DDLDataTypes_BinaryString, ValueType: protocol.DDLDataTypes_List, Volatile: true},
+		protocol.TypedKeyMapDescription{KeyType: protocol.DDLTypeOf(protocol.DDLDataTypes_String), ValueType: protocol.ListOf(protocol.DDLTypeOf(protocol.DDLDataTypes_BinaryString))},
+		protocol.GenericModelDescription{Kind: "Keymap", Types: []protocol.DDLType{{Name: "str"}, {Name: "str"}}, Flags: []string{"volatile", "compressed"}},
+	} {
+		got, err := FormatSingleCreateTablePacket("ks:t", desc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var b strings.Builder
+		b.WriteString("*1\n")
+		if err := (CreateTable{Path: "ks:t", ModelDescription: desc}).AppendToPacket(&b); err != nil {
+			t.Fatal(err)
+		}
+
+		if got != b.String() {
+			t.Errorf("FormatSingleCreateTablePacket() = %q, want %q", got, b.String())
+		}
+	}
+}
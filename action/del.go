@@ -41,3 +41,8 @@ func (q Del) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("DEL: Unexpected response element: %v", response), nil)
 	}
 }
+
+// A repeated DEL reports fewer keys deleted than the first one did, so it's not retried.
+func (q Del) Idempotent() bool {
+	return false
+}
@@ -44,3 +44,8 @@ func (q DropKeyspace) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("DropKeyspace: Unexpected response element: %v", response), nil)
 	}
 }
+
+// A repeated DROP KEYSPACE fails with container-not-found although the first one may have dropped it, so it's not retried.
+func (q DropKeyspace) Idempotent() bool {
+	return false
+}
@@ -43,3 +43,8 @@ func (q DropTable) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("DropTable: Unexpected response element: %v", response), nil)
 	}
 }
+
+// A repeated DROP TABLE fails with container-not-found although the first one may have dropped it, so it's not retried.
+func (q DropTable) Idempotent() bool {
+	return false
+}
@@ -0,0 +1,129 @@
+package action
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/No3371/go-skytable/protocol"
+)
+
+// Encoder converts a value into an element natively supported by [AppendElement],
+// for example a string or a []byte.
+type Encoder func(v any) (any, error)
+
+var encoders sync.Map // reflect.Type -> Encoder
+
+var stringerEncoding int32 // atomic
+
+// RegisterEncoder registers an encoder for values of type T.
+// Registered encoders take precedence over all the other encoding rules of values not natively supported.
+//
+//	action.RegisterEncoder(func(id uuid.UUID) (any, error) {
+//		return id[:], nil
+//	})
+func RegisterEncoder[T any](enc func(v T) (any, error)) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	encoders.Store(t, Encoder(func(v any) (any, error) {
+		return enc(v.(T))
+	}))
+}
+
+// UnregisterEncoder removes the encoder registered for values of type T, if any.
+func UnregisterEncoder[T any]() {
+	encoders.Delete(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// EnableStringerEncoding makes [AppendElement] encode values implementing fmt.Stringer with String(),
+// when no other rule applies. It's disabled by default, because String() is usually meant for humans.
+func EnableStringerEncoding(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&stringerEncoding, 1)
+	} else {
+		atomic.StoreInt32(&stringerEncoding, 0)
+	}
+}
+
+// isNativeElement reports whether v is handled by [AppendElement] without extended encoding.
+func isNativeElement(v any) bool {
+	switch v.(type) {
+	case string, int8, uint8, int, int32, int64, uint, uint32, uint64, float32, []byte, *protocol.TypedArray, *protocol.Array:
+		return true
+	default:
+		return false
+	}
+}
+
+// encodeExtended converts values not natively supported by [AppendElement], see [AppendElement] for the order of rules.
+func encodeExtended(v any) (any, error) {
+	if enc, registered := encoders.Load(reflect.TypeOf(v)); registered {
+		encoded, err := enc.(Encoder)(v)
+		if err != nil {
+			return nil, fmt.Errorf("encoder of %T: %w", v, err)
+		}
+		if encoded != nil && !isNativeElement(encoded) {
+			return nil, protocol.NewUnexpectedProtocolError(fmt.Sprintf("Encoder of %T returned an unexpected element: %v (%T)", v, encoded, encoded), nil)
+		}
+		return encoded, nil
+	}
+
+	// The marshalers of typed nils like (*time.Time)(nil) would dereference them
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return nil, protocol.NewUnexpectedProtocolError(fmt.Sprintf("Appending a nil pointer: %T", v), nil)
+	}
+
+	switch v := v.(type) {
+	case encoding.TextMarshaler:
+		text, err := v.MarshalText()
+		if err != nil {
+			return nil, fmt.Errorf("MarshalText() of %T: %w", v, err)
+		}
+		return string(text), nil
+	case encoding.BinaryMarshaler:
+		bin, err := v.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("MarshalBinary() of %T: %w", v, err)
+		}
+		return bin, nil
+	case json.Marshaler:
+		j, err := v.MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("MarshalJSON() of %T: %w", v, err)
+		}
+		return string(j), nil
+	case fmt.Stringer:
+		if atomic.LoadInt32(&stringerEncoding) == 1 {
+			return v.String(), nil
+		}
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Int8:
+		return int8(rv.Int()), nil
+	case reflect.Int, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint8:
+		return uint8(rv.Uint()), nil
+	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint(), nil
+	case reflect.Float32:
+		return float32(rv.Float()), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, 64), nil
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv.Bytes(), nil
+		}
+	}
+
+	return nil, protocol.NewUnexpectedProtocolError(fmt.Sprintf("Appending an unexpected element: %v (%T)", v, v), nil)
+}
@@ -0,0 +1,85 @@
+package action
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testLevel string
+
+type testID struct{ n byte }
+
+type testColor int
+
+func (c testColor) String() string { return "color" }
+
+type testJSON struct{}
+
+func (testJSON) MarshalJSON() ([]byte, error) { return []byte(`{"a":1}`), nil }
+
+func TestAppendElement_Extended(t *testing.T) {
+	ts := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	RegisterEncoder(func(id testID) (any, error) {
+		return []byte{id.n}, nil
+	})
+	defer UnregisterEncoder[testID]()
+
+	tests := []struct {
+		name    string
+		v       any
+		want    string
+		wantErr bool
+	}{
+		{"bool", true, "4\ntrue\n", false},
+		{"int16", int16(42), "2\n42\n", false},
+		{"uint16", uint16(7), "1\n7\n", false},
+		{"float64", 1.5, "3\n1.5\n", false},
+		{"named string", testLevel("warn"), "4\nwarn\n", false},
+		{"time.Time", ts, "20\n2022-10-01T12:00:00Z\n", false},
+		{"json.Marshaler", testJSON{}, "7\n{\"a\":1}\n", false},
+		{"registered", testID{'z'}, "1\nz\n", false},
+		{"stringer disabled", testColor(3), "1\n3\n", false},
+		{"unsupported", map[string]int{}, "", true},
+		{"nil *time.Time", (*time.Time)(nil), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &strings.Builder{}
+			err := AppendElement(b, false, tt.v)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AppendElement() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := b.String(); !tt.wantErr && got != tt.want {
+				t.Errorf("AppendElement() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendElement_Stringer(t *testing.T) {
+	EnableStringerEncoding(true)
+	defer EnableStringerEncoding(false)
+
+	b := &strings.Builder{}
+	if err := AppendElement(b, false, testColor(3)); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := b.String(), "5\ncolor\n"; got != want {
+		t.Errorf("AppendElement() = %q, want %q", got, want)
+	}
+}
+
+func TestAppendElement_EncoderError(t *testing.T) {
+	errEncode := errors.New("encode")
+	RegisterEncoder(func(testLevel) (any, error) {
+		return nil, errEncode
+	})
+	defer UnregisterEncoder[testLevel]()
+
+	if err := AppendElement(&strings.Builder{}, false, testLevel("x")); !errors.Is(err, errEncode) {
+		t.Errorf("AppendElement() error = %v, want %v", err, errEncode)
+	}
+}
@@ -40,3 +40,7 @@ func (q Exists) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("EXISTS: Unexpected response element: %v", response), nil)
 	}
 }
+
+func (q Exists) Idempotent() bool {
+	return true
+}
@@ -45,3 +45,7 @@ func (q Get) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("GET: Unexpected response element: %v", response), nil)
 	}
 }
+
+func (q Get) Idempotent() bool {
+	return true
+}
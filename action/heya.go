@@ -48,3 +48,7 @@ func (q Heya) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("HEYA: Unexpected response element: %v", response), nil)
 	}
 }
+
+func (q Heya) Idempotent() bool {
+	return true
+}
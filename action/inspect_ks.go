@@ -67,3 +67,7 @@ func (q InspectKeyspace) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("InspectKeyspace: Unexpected response element: %v", response), nil)
 	}
 }
+
+func (q InspectKeyspace) Idempotent() bool {
+	return true
+}
@@ -42,3 +42,7 @@ func (q InspectKeyspaces) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("InspectKeyspaces: Unexpected response element: %v", response), nil)
 	}
 }
+
+func (q InspectKeyspaces) Idempotent() bool {
+	return true
+}
@@ -61,3 +61,7 @@ func (q InspectTable) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("InspectTable: Unexpected response element: %v", response), nil)
 	}
 }
+
+func (q InspectTable) Idempotent() bool {
+	return true
+}
@@ -39,3 +39,7 @@ func (q KeyLen) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("KeyLen: Unexpected response element: %v", response), nil)
 	}
 }
+
+func (q KeyLen) Idempotent() bool {
+	return true
+}
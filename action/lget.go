@@ -49,6 +49,10 @@ func (q LGet) ValidateProtocol(response interface{}) error {
 	}
 }
 
+func (q LGet) Idempotent() bool {
+	return true
+}
+
 // https://docs.skytable.io/actions/lget#len
 type LGetLen struct {
 	ListName string
@@ -68,7 +72,16 @@ func (q LGetLen) AppendToPacket(builder *strings.Builder) error {
 }
 
 func (q LGetLen) ValidateProtocol(response interface{}) error {
-	switch response.(type) {
+	switch response := response.(type) {
+	case protocol.ResponseCode:
+		switch response {
+		case protocol.RespNil:
+			return nil
+		case protocol.RespServerError:
+			return nil
+		default:
+			return protocol.NewUnexpectedProtocolError(fmt.Sprintf("LGETLEN: Unexpected response code: %v", response), nil)
+		}
 	case uint64:
 		return nil
 	default:
@@ -76,6 +89,10 @@ func (q LGetLen) ValidateProtocol(response interface{}) error {
 	}
 }
 
+func (q LGetLen) Idempotent() bool {
+	return true
+}
+
 // https://docs.skytable.io/actions/lget#valueat
 type LGetValueAt struct {
 	ListName string
@@ -118,6 +135,10 @@ func (q LGetValueAt) ValidateProtocol(response interface{}) error {
 	}
 }
 
+func (q LGetValueAt) Idempotent() bool {
+	return true
+}
+
 // https://docs.skytable.io/actions/lget#first
 type LGetFirst struct {
 	ListName string
@@ -159,6 +180,10 @@ func (q LGetFirst) ValidateProtocol(response interface{}) error {
 	}
 }
 
+func (q LGetFirst) Idempotent() bool {
+	return true
+}
+
 // https://docs.skytable.io/actions/lget#last
 type LGetLast struct {
 	ListName string
@@ -200,6 +225,10 @@ func (q LGetLast) ValidateProtocol(response interface{}) error {
 	}
 }
 
+func (q LGetLast) Idempotent() bool {
+	return true
+}
+
 // https://docs.skytable.io/actions/lget#range
 type LGetRange struct {
 	ListName string
@@ -250,3 +279,7 @@ func (q LGetRange) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("LGETRANGE: Unexpected response element: %v", response), nil)
 	}
 }
+
+func (q LGetRange) Idempotent() bool {
+	return true
+}
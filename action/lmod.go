@@ -55,6 +55,10 @@ func (q LModPush) ValidateProtocol(response any) error {
 	}
 }
 
+func (q LModPush) Idempotent() bool {
+	return false
+}
+
 // https://docs.skytable.io/actions/lmod#insert
 type LModInsert struct {
 	ListName   string
@@ -101,6 +105,10 @@ func (q LModInsert) ValidateProtocol(response any) error {
 	}
 }
 
+func (q LModInsert) Idempotent() bool {
+	return false
+}
+
 // https://docs.skytable.io/actions/lmod#pop
 type LModPop struct {
 	ListName string
@@ -142,6 +150,10 @@ func (q LModPop) ValidateProtocol(response interface{}) error {
 	}
 }
 
+func (q LModPop) Idempotent() bool {
+	return false
+}
+
 // https://docs.skytable.io/actions/lmod#pop
 type LModPopIndex struct {
 	ListName string
@@ -184,6 +196,10 @@ func (q LModPopIndex) ValidateProtocol(response interface{}) error {
 	}
 }
 
+func (q LModPopIndex) Idempotent() bool {
+	return false
+}
+
 // https://docs.skytable.io/actions/lmod#remove
 type LModRemove struct {
 	ListName string
@@ -224,6 +240,11 @@ func (q LModRemove) ValidateProtocol(response interface{}) error {
 	}
 }
 
+// A repeated REMOVE removes the element shifted into the index.
+func (q LModRemove) Idempotent() bool {
+	return false
+}
+
 // https://docs.skytable.io/actions/lmod#clear
 type LModClear struct {
 	ListName string
@@ -259,4 +280,8 @@ func (q LModClear) ValidateProtocol(response interface{}) error {
 	default:
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("LMODCLEAR: Unexpected response element: %v", response), nil)
 	}
-}
\ No newline at end of file
+}
+
+func (q LModClear) Idempotent() bool {
+	return true
+}
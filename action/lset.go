@@ -53,3 +53,8 @@ func (q LSet) ValidateProtocol(response any) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("LSET: Unexpected response element: %v", response), nil)
 	}
 }
+
+// A repeated LSET fails with Overwrite although the first one may have created the list, so it's not retried.
+func (q LSet) Idempotent() bool {
+	return false
+}
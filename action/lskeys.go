@@ -74,3 +74,7 @@ func (q LSKeys) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("LSKEYS: Unexpected response element: %v", response), nil)
 	}
 }
+
+func (q LSKeys) Idempotent() bool {
+	return true
+}
@@ -40,3 +40,7 @@ func (q MGet) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("MGET: Unexpected response element: %v", response), nil)
 	}
 }
+
+func (q MGet) Idempotent() bool {
+	return true
+}
@@ -65,3 +65,8 @@ func (q MKSnap) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("MKSnap: Unexpected response element: %v", response), nil)
 	}
 }
+
+// A repeated named MKSNAP fails as the first one may have created the snapshot, so only unnamed ones are retried.
+func (q MKSnap) Idempotent() bool {
+	return q.Name == ""
+}
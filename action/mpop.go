@@ -47,3 +47,7 @@ func (q MPop) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("MPOP: Unexpected response element: %v", response), nil)
 	}
 }
+
+func (q MPop) Idempotent() bool {
+	return false
+}
@@ -53,6 +53,11 @@ func (q MSetA) ValidateProtocol(response interface{}) error {
 	}
 }
 
+// A repeated MSET skips the keys set by the first one, reporting fewer keys set, so it's not retried.
+func (q MSetA) Idempotent() bool {
+	return false
+}
+
 // https://docs.skytable.io/actions/mset
 type MSetB struct {
 	Keys   []string
@@ -84,3 +89,7 @@ func (q MSetB) ValidateProtocol(response any) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("MSET: Unexpected response element: %v", response), nil)
 	}
 }
+
+func (q MSetB) Idempotent() bool {
+	return false
+}
@@ -52,3 +52,7 @@ func (q MUpdate) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("MUpdate: Unexpected response element: %v", response), nil)
 	}
 }
+
+func (q MUpdate) Idempotent() bool {
+	return true
+}
@@ -45,3 +45,7 @@ func (q Pop) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("Pop: Unexpected response element: %v", response), nil)
 	}
 }
+
+func (q Pop) Idempotent() bool {
+	return false
+}
@@ -50,3 +50,8 @@ func (q SDel) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("SDEL: Unexpected response element: %v", response), nil)
 	}
 }
+
+// A repeated SDEL fails with Nil although the first one may have deleted the keys, so it's not retried.
+func (q SDel) Idempotent() bool {
+	return false
+}
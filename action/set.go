@@ -43,3 +43,8 @@ func (q Set) ValidateProtocol(response any) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("SET: Unexpected response element: %v", response), nil)
 	}
 }
+
+// A repeated SET fails with Overwrite although the first one may have set the value, so it's not retried.
+func (q Set) Idempotent() bool {
+	return false
+}
@@ -54,3 +54,8 @@ func (q SSet) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("SSET: Unexpected response element: %v", response), nil)
 	}
 }
+
+// A repeated SSET fails with Overwrite although the first one may have set the values, so it's not retried.
+func (q SSet) Idempotent() bool {
+	return false
+}
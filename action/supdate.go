@@ -54,3 +54,7 @@ func (q SUpdate) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("SUPDATE: Unexpected response element: %v", response), nil)
 	}
 }
+
+func (q SUpdate) Idempotent() bool {
+	return true
+}
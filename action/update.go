@@ -44,3 +44,7 @@ func (q Update) ValidateProtocol(response any) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("Update: Unexpected response element: %v", response), nil)
 	}
 }
+
+func (q Update) Idempotent() bool {
+	return true
+}
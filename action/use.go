@@ -51,3 +51,7 @@ func (q Use) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("Use: Unexpected response element: %v", response), nil)
 	}
 }
+
+func (q Use) Idempotent() bool {
+	return true
+}
@@ -48,3 +48,7 @@ func (q USet) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("USET: Unexpected response element: %v", response), nil)
 	}
 }
+
+func (q USet) Idempotent() bool {
+	return true
+}
@@ -38,3 +38,7 @@ func (q WhereAmI) ValidateProtocol(response interface{}) error {
 		return protocol.NewUnexpectedProtocolError(fmt.Sprintf("WhereAmI: Unexpected response element: %v", response), nil)
 	}
 }
+
+func (q WhereAmI) Idempotent() bool {
+	return true
+}
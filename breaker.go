@@ -0,0 +1,122 @@
+package skytable
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/No3371/go-skytable/protocol"
+)
+
+// BreakerOptions enables the circuit breaker of a [ConnPool].
+//
+// After Threshold consecutive dial or communication failures, the breaker opens:
+// the methods of the pool fail fast with [ErrCircuitOpen] for the Cooldown.
+// Then the breaker half-opens, the next call dials a new conn and probes it with HEYA,
+// closing the breaker if it succeeds or opening it again for another Cooldown if it fails.
+type BreakerOptions struct {
+	Threshold int           // Consecutive failures opening the breaker, 5 if 0
+	Cooldown  time.Duration // How long the breaker stays open before probing, 5 seconds if 0
+}
+
+// ErrCircuitOpen is returned by the methods of a [ConnPool] while its circuit breaker is open.
+type ErrCircuitOpen struct {
+	Until   time.Time // When the breaker half-opens
+	lastErr error
+}
+
+func (err ErrCircuitOpen) Error() string {
+	return fmt.Sprintf("circuit breaker open until %s, last failure: %s", err.Until.Format(time.RFC3339Nano), err.lastErr)
+}
+
+func (err ErrCircuitOpen) Unwrap() error {
+	return err.lastErr
+}
+
+type breakerState byte
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen // A probe is in flight
+)
+
+type breaker struct {
+	opts BreakerOptions
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	until    time.Time
+	lastErr  error
+}
+
+func newBreaker(opts BreakerOptions) *breaker {
+	if opts.Threshold <= 0 {
+		opts.Threshold = 5
+	}
+	if opts.Cooldown <= 0 {
+		opts.Cooldown = 5 * time.Second
+	}
+
+	return &breaker{opts: opts}
+}
+
+// allow returns an error if the call should fail fast, or probe = true if the caller should probe the server.
+func (b *breaker) allow() (probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Now().Before(b.until) {
+			return false, b.openError()
+		}
+		b.state = breakerHalfOpen
+		return true, nil
+	case breakerHalfOpen:
+		return false, b.openError()
+	default:
+		return false, nil
+	}
+}
+
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = breakerClosed
+	b.failures = 0
+}
+
+func (b *breaker) failure(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastErr = err
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.opts.Threshold {
+		b.state = breakerOpen
+		b.until = time.Now().Add(b.opts.Cooldown)
+	}
+}
+
+// b.mu must be held.
+func (b *breaker) openError() ErrCircuitOpen {
+	return ErrCircuitOpen{
+		Until:   b.until,
+		lastErr: b.lastErr,
+	}
+}
+
+// isComuFailure returns true if err closed the conn due to a transport failure,
+// but not due to a response like a packet error.
+func isComuFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var errCode *protocol.ErrorCodeResponse
+	return !errors.As(err, &errCode)
+}
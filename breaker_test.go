@@ -0,0 +1,87 @@
+package skytable_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/skytabletest"
+)
+
+func TestConnPoolBreaker(t *testing.T) {
+	ctx := context.Background()
+	cooldown := 50 * time.Millisecond
+
+	p, err := skytabletest.NewProxy(noAuthAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := p.Addr()
+
+	cp := skytable.NewConnPool(addr, skytable.ConnPoolOptions{
+		Cap:     2,
+		Breaker: &skytable.BreakerOptions{Threshold: 2, Cooldown: cooldown},
+	})
+
+	if err := cp.Heya(ctx, ""); err != nil {
+		t.Fatal(err)
+	}
+	_, pushIdle, err := cp.RentConn(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The node goes down
+	p.Close()
+
+	var errOpen skytable.ErrCircuitOpen
+	for i := 0; i < 2; i++ {
+		err := cp.Heya(ctx, "")
+		if err == nil {
+			t.Fatal("expecting failure")
+		}
+		if errors.As(err, &errOpen) {
+			t.Fatalf("expecting the breaker to be closed before %d failures: %s", 2, err)
+		}
+	}
+
+	if err := cp.Heya(ctx, ""); !errors.As(err, &errOpen) {
+		t.Fatalf("expecting ErrCircuitOpen but got %v", err)
+	}
+
+	// Pushing back a conn which sent nothing does not close the breaker
+	pushIdle()
+	if err := cp.Heya(ctx, ""); !errors.As(err, &errOpen) {
+		t.Fatalf("expecting ErrCircuitOpen after pushing back an idle conn but got %v", err)
+	}
+
+	// Still down when half-open, the probe fails
+	time.Sleep(cooldown)
+	if err := cp.Heya(ctx, ""); !errors.As(err, &errOpen) {
+		t.Fatalf("expecting ErrCircuitOpen but got %v", err)
+	}
+	if !errOpen.Until.After(time.Now()) {
+		t.Fatal("expecting the breaker to be opened again")
+	}
+
+	// The node is back
+	srv, err := skytabletest.NewServer(skytabletest.Options{Addr: addr.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+
+	time.Sleep(cooldown)
+	if err := cp.Heya(ctx, ""); err != nil {
+		t.Fatalf("expecting the probe to close the breaker but got %v", err)
+	}
+	// The idle conn was cut when the node went down, it fails once without opening the breaker
+	if err := cp.Heya(ctx, ""); err == nil || errors.As(err, &errOpen) {
+		t.Fatalf("expecting the idle conn to fail but got %v", err)
+	}
+	if err := cp.Heya(ctx, ""); err != nil {
+		t.Fatal(err)
+	}
+}
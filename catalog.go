@@ -0,0 +1,220 @@
+package skytable
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/No3371/go-skytable/protocol"
+)
+
+// TableInfo describes a table, see [Catalog].
+type TableInfo struct {
+	Path     string // The entity path, "keyspace:table"
+	Keyspace string
+	Name     string
+	Model    protocol.ModelDescription // Compare it with protocol.SameModel
+	Volatile bool
+	Size     uint64 // The DBSIZE of the table when it was described
+}
+
+type CatalogOptions struct {
+	Cache  bool          // If true, the results are kept until Refresh
+	MaxAge time.Duration // If Cache is true and MaxAge > 0, the results are also refreshed after MaxAge
+}
+
+// Catalog lists the keyspaces and the tables of a server, as typed results of INSPECT and DBSIZE.
+type Catalog struct {
+	db   Skytable
+	opts CatalogOptions
+
+	mu        sync.Mutex
+	keyspaces *catalogEntry[[]string]
+	tables    map[string]catalogEntry[[]string] // Table names by keyspace
+	described map[string]catalogEntry[TableInfo]
+}
+
+type catalogEntry[T any] struct {
+	value T
+	at    time.Time
+}
+
+// NewCatalog creates a Catalog inspecting the server through db.
+func NewCatalog(db Skytable, opts CatalogOptions) *Catalog {
+	c := &Catalog{
+		db:   db,
+		opts: opts,
+	}
+	c.Refresh()
+
+	return c
+}
+
+// Refresh drops the cached results.
+func (c *Catalog) Refresh() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.keyspaces = nil
+	c.tables = make(map[string]catalogEntry[[]string])
+	c.described = make(map[string]catalogEntry[TableInfo])
+}
+
+// RefreshKeyspace drops the cached results of the keyspace and its tables.
+func (c *Catalog) RefreshKeyspace(keyspace string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.keyspaces = nil
+	delete(c.tables, keyspace)
+	for path := range c.described {
+		if ks, _, _ := strings.Cut(path, ":"); ks == keyspace {
+			delete(c.described, path)
+		}
+	}
+}
+
+func (c *Catalog) fresh(at time.Time) bool {
+	return c.opts.Cache && (c.opts.MaxAge <= 0 || time.Since(at) < c.opts.MaxAge)
+}
+
+// ListKeyspaces returns the names of the keyspaces, as INSPECT KEYSPACES reports them.
+func (c *Catalog) ListKeyspaces(ctx context.Context) ([]string, error) {
+	c.mu.Lock()
+	if c.keyspaces != nil && c.fresh(c.keyspaces.at) {
+		keyspaces := append([]string(nil), c.keyspaces.value...)
+		c.mu.Unlock()
+		return keyspaces, nil
+	}
+	c.mu.Unlock()
+
+	arr, err := c.db.InspectKeyspaces(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("*Catalog.ListKeyspaces(): %w", err)
+	}
+	keyspaces := catalogNames(arr)
+
+	if c.opts.Cache {
+		c.mu.Lock()
+		c.keyspaces = &catalogEntry[[]string]{append([]string(nil), keyspaces...), time.Now()}
+		c.mu.Unlock()
+	}
+
+	return keyspaces, nil
+}
+
+// ListTables describes the tables of the keyspace, see DescribeTable.
+func (c *Catalog) ListTables(ctx context.Context, keyspace string) ([]TableInfo, error) {
+	names, err := c.tableNames(ctx, keyspace)
+	if err != nil {
+		return nil, fmt.Errorf("*Catalog.ListTables(): %s: %w", keyspace, err)
+	}
+
+	tables := make([]TableInfo, 0, len(names))
+	for _, name := range names {
+		info, err := c.describe(ctx, keyspace+":"+name)
+		if err != nil {
+			return nil, fmt.Errorf("*Catalog.ListTables(): %w", err)
+		}
+		tables = append(tables, info)
+	}
+
+	return tables, nil
+}
+
+func (c *Catalog) tableNames(ctx context.Context, keyspace string) ([]string, error) {
+	c.mu.Lock()
+	if e, cached := c.tables[keyspace]; cached && c.fresh(e.at) {
+		c.mu.Unlock()
+		return e.value, nil
+	}
+	c.mu.Unlock()
+
+	arr, err := c.db.InspectKeyspace(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	names := catalogNames(arr)
+
+	if c.opts.Cache {
+		c.mu.Lock()
+		c.tables[keyspace] = catalogEntry[[]string]{names, time.Now()}
+		c.mu.Unlock()
+	}
+
+	return names, nil
+}
+
+// DescribeTable returns the model and the size of the table, path being "keyspace:table".
+func (c *Catalog) DescribeTable(ctx context.Context, path string) (TableInfo, error) {
+	info, err := c.describe(ctx, path)
+	if err != nil {
+		return TableInfo{}, fmt.Errorf("*Catalog.DescribeTable(): %w", err)
+	}
+
+	return info, nil
+}
+
+func (c *Catalog) describe(ctx context.Context, path string) (TableInfo, error) {
+	keyspace, name, found := strings.Cut(path, ":")
+	if !found {
+		return TableInfo{}, fmt.Errorf("%s: use explicit full path to the table (keyspace:table)", path)
+	}
+
+	c.mu.Lock()
+	if e, cached := c.described[path]; cached && c.fresh(e.at) {
+		c.mu.Unlock()
+		return e.value, nil
+	}
+	c.mu.Unlock()
+
+	model, err := c.db.InspectTable(ctx, path)
+	if err != nil {
+		return TableInfo{}, fmt.Errorf("%s: %w", path, err)
+	}
+	size, err := c.db.DBSize(ctx, path)
+	if err != nil {
+		return TableInfo{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	info := TableInfo{
+		Path:     path,
+		Keyspace: keyspace,
+		Name:     name,
+		Model:    model,
+		Volatile: isVolatile(model),
+		Size:     size,
+	}
+
+	if c.opts.Cache {
+		c.mu.Lock()
+		c.described[path] = catalogEntry[TableInfo]{info, time.Now()}
+		c.mu.Unlock()
+	}
+
+	return info, nil
+}
+
+func isVolatile(model protocol.ModelDescription) bool {
+	for _, p := range strings.Fields(model.Properties()) {
+		if p == "volatile" {
+			return true
+		}
+	}
+	return false
+}
+
+func catalogNames(arr *protocol.TypedArray) []string {
+	names := make([]string, 0, len(arr.Elements))
+	for _, e := range arr.Elements {
+		switch e := e.(type) {
+		case []byte:
+			names = append(names, string(e))
+		default:
+			names = append(names, fmt.Sprint(e))
+		}
+	}
+	return names
+}
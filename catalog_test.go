@@ -0,0 +1,120 @@
+package skytable_test
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/protocol"
+)
+
+func TestCatalog(t *testing.T) {
+	ctx := context.Background()
+	c := newSchemaTestConn(t)
+
+	if _, err := skytable.EnsureSchema(ctx, c, testSchema, skytable.EnsureSchemaOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Use(ctx, "app:users"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set(ctx, "alice", []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+
+	catalog := skytable.NewCatalog(c, skytable.CatalogOptions{})
+
+	keyspaces, err := catalog.ListKeyspaces(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(keyspaces)
+	if !reflect.DeepEqual(keyspaces, []string{"app", "default"}) {
+		t.Errorf("ListKeyspaces() = %v", keyspaces)
+	}
+
+	tables, err := catalog.ListTables(ctx, "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 3 {
+		t.Fatalf("ListTables() = %v, want 3 tables", tables)
+	}
+	for _, info := range tables {
+		var want skytable.TableSchema
+		for _, ts := range testSchema.Keyspaces[0].Tables {
+			if ts.Name == info.Name {
+				want = ts
+			}
+		}
+		if info.Path != "app:"+want.Name || info.Keyspace != "app" || !protocol.SameModel(info.Model, want.Model) {
+			t.Errorf("ListTables(): %+v, want %s %v", info, want.Name, want.Model)
+		}
+		if info.Volatile != (info.Name == "sessions") {
+			t.Errorf("%s: Volatile = %t", info.Path, info.Volatile)
+		}
+		if info.Name == "users" && info.Size != 1 {
+			t.Errorf("%s: Size = %d, want 1", info.Path, info.Size)
+		}
+	}
+
+	info, err := catalog.DescribeTable(ctx, "app:queues")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Model.Model() != "keymap(str,list<str>)" {
+		t.Errorf("DescribeTable() model = %s", info.Model.Model())
+	}
+
+	if _, err := catalog.DescribeTable(ctx, "app:missing"); err == nil {
+		t.Error("expecting an error describing a missing table")
+	}
+}
+
+func TestCatalog_Cache(t *testing.T) {
+	ctx := context.Background()
+	c := newSchemaTestConn(t)
+
+	if _, err := skytable.EnsureSchema(ctx, c, testSchema, skytable.EnsureSchemaOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	catalog := skytable.NewCatalog(c, skytable.CatalogOptions{Cache: true})
+	tables, err := catalog.ListTables(ctx, "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.CreateTable(ctx, "app:extra", protocol.KeyMapDescription{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Use(ctx, "app:users"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set(ctx, "alice", []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+
+	cached, err := catalog.ListTables(ctx, "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cached, tables) {
+		t.Errorf("ListTables() = %v, want the cached %v", cached, tables)
+	}
+
+	catalog.RefreshKeyspace("app")
+	refreshed, err := catalog.ListTables(ctx, "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(refreshed) != 4 {
+		t.Fatalf("ListTables() = %v, want 4 tables after a refresh", refreshed)
+	}
+	info, err := catalog.DescribeTable(ctx, "app:users")
+	if err != nil || info.Size != 1 {
+		t.Errorf("DescribeTable() = %+v, %v, want a size of 1 after a refresh", info, err)
+	}
+}
@@ -0,0 +1,247 @@
+package skytable
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNoHealthyNode is returned by a [Cluster] when all the nodes able to serve the call are unhealthy.
+var ErrNoHealthyNode = errors.New("cluster: no healthy node")
+
+type NodeSelection byte
+
+const (
+	// Rotate the calls over the healthy nodes.
+	SelectRoundRobin NodeSelection = iota
+	// Send the call to the healthy node with the least calls in flight.
+	SelectLeastBusy
+)
+
+type ClusterOptions struct {
+	Selection           NodeSelection
+	HealthCheckInterval time.Duration // How often the nodes are checked with HEYA, 5 seconds if 0
+	// If true, writes go to a healthy replica while the primary is unhealthy,
+	// only enable it if your replicas are able to take writes.
+	FailoverWrites bool
+}
+
+var DefaultClusterOptions = ClusterOptions{
+	Selection:           SelectRoundRobin,
+	HealthCheckInterval: 5 * time.Second,
+}
+
+type clusterNode struct {
+	pool     *ConnPool
+	healthy  int32 // atomic, 1 if healthy
+	inflight int64 // atomic
+}
+
+func (n *clusterNode) isHealthy() bool {
+	return atomic.LoadInt32(&n.healthy) == 1
+}
+
+func (n *clusterNode) setHealthy(healthy bool) {
+	if healthy {
+		atomic.StoreInt32(&n.healthy, 1)
+	} else {
+		atomic.StoreInt32(&n.healthy, 0)
+	}
+}
+
+// Cluster spreads the calls over multiple Skytable instances, each accessed with a [ConnPool].
+//
+// Reads (GET, MGET and LGET, including their variants) go to the healthy Replicas, falling back to the Primary,
+// and are retried on the next node if a node fails. Everything else goes to the Primary,
+// except AUTH LOGIN/LOGOUT and USE which are sent to all the nodes.
+//
+// The nodes are checked with HEYA in background, and a node failing a call is marked as unhealthy until it passes a check.
+// Skytable does not replicate data, keeping the Replicas in sync is up to you.
+type Cluster struct {
+	primary  *clusterNode
+	replicas []*clusterNode
+	opts     ClusterOptions
+
+	rr uint64 // atomic
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewCluster creates a Cluster and starts checking the health of the nodes.
+// DefaultClusterOptions is available for the `opts` argument.
+func NewCluster(primary *ConnPool, replicas []*ConnPool, opts ClusterOptions) *Cluster {
+	if opts.HealthCheckInterval <= 0 {
+		opts.HealthCheckInterval = 5 * time.Second
+	}
+
+	c := &Cluster{
+		primary: &clusterNode{pool: primary, healthy: 1},
+		opts:    opts,
+		stop:    make(chan struct{}),
+	}
+
+	for _, r := range replicas {
+		c.replicas = append(c.replicas, &clusterNode{pool: r, healthy: 1})
+	}
+
+	c.wg.Add(1)
+	go c.checkHealth()
+
+	return c
+}
+
+// Primary returns the pool of the primary node.
+func (c *Cluster) Primary() *ConnPool {
+	return c.primary.pool
+}
+
+// Replicas returns the pools of the replica nodes.
+func (c *Cluster) Replicas() []*ConnPool {
+	pools := make([]*ConnPool, len(c.replicas))
+	for i, r := range c.replicas {
+		pools[i] = r.pool
+	}
+	return pools
+}
+
+// Close stops the health checks. The pools are not affected. It can be called more than once.
+func (c *Cluster) Close() {
+	c.stopOnce.Do(func() { close(c.stop) })
+	c.wg.Wait()
+}
+
+func (c *Cluster) checkHealth() {
+	defer c.wg.Done()
+
+	t := time.NewTicker(c.opts.HealthCheckInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-t.C:
+		}
+
+		nodes := append([]*clusterNode{c.primary}, c.replicas...)
+		wg := sync.WaitGroup{}
+		for _, n := range nodes {
+			wg.Add(1)
+			go func(n *clusterNode) {
+				defer wg.Done()
+				n.setHealthy(n.pool.Heya(context.Background(), "") == nil)
+			}(n)
+		}
+		wg.Wait()
+	}
+}
+
+// candidates returns the healthy nodes in the order they should be tried.
+func (c *Cluster) candidates(nodes []*clusterNode) []*clusterNode {
+	healthy := make([]*clusterNode, 0, len(nodes))
+	for _, n := range nodes {
+		if n.isHealthy() {
+			healthy = append(healthy, n)
+		}
+	}
+
+	if len(healthy) < 2 {
+		return healthy
+	}
+
+	start := int(atomic.AddUint64(&c.rr, 1) % uint64(len(healthy)))
+	healthy = append(healthy[start:], healthy[:start]...)
+
+	if c.opts.Selection == SelectLeastBusy {
+		least := 0
+		for i, n := range healthy {
+			if atomic.LoadInt64(&n.inflight) < atomic.LoadInt64(&healthy[least].inflight) {
+				least = i
+			}
+		}
+		healthy[0], healthy[least] = healthy[least], healthy[0]
+	}
+
+	return healthy
+}
+
+// isNodeFailure returns true if err means the node is unreachable, rather than a response of the node.
+func isNodeFailure(err error) bool {
+	var errComu ErrComu
+	var errOpen ErrCircuitOpen
+	var errNet net.Error
+	return errors.As(err, &errComu) || errors.As(err, &errOpen) || errors.As(err, &errNet)
+}
+
+func call[T any](n *clusterNode, f func(p *ConnPool) (T, error)) (T, error) {
+	atomic.AddInt64(&n.inflight, 1)
+	defer atomic.AddInt64(&n.inflight, -1)
+
+	v, err := f(n.pool)
+	if err != nil && isNodeFailure(err) {
+		n.setHealthy(false)
+	}
+	return v, err
+}
+
+// onReplica calls f with the replicas, then the primary, until one of them does not fail as a node.
+func onReplica[T any](c *Cluster, f func(p *ConnPool) (T, error)) (v T, err error) {
+	nodes := c.candidates(c.replicas)
+	if c.primary.isHealthy() {
+		nodes = append(nodes, c.primary)
+	}
+
+	err = ErrNoHealthyNode
+	for _, n := range nodes {
+		v, err = call(n, f)
+		if err == nil || !isNodeFailure(err) {
+			return v, err
+		}
+	}
+
+	return v, err
+}
+
+// onPrimary calls f with the primary, or with a healthy replica if the primary is unhealthy and FailoverWrites is enabled.
+func onPrimary[T any](c *Cluster, f func(p *ConnPool) (T, error)) (v T, err error) {
+	n := c.primary
+	if !n.isHealthy() {
+		if !c.opts.FailoverWrites {
+			return v, ErrNoHealthyNode
+		}
+
+		replicas := c.candidates(c.replicas)
+		if len(replicas) == 0 {
+			return v, ErrNoHealthyNode
+		}
+		n = replicas[0]
+	}
+
+	return call(n, f)
+}
+
+func (c *Cluster) doPrimary(f func(p *ConnPool) error) error {
+	_, err := onPrimary(c, func(p *ConnPool) (struct{}, error) {
+		return struct{}{}, f(p)
+	})
+	return err
+}
+
+// onAll calls f with every node, returning the first error.
+func (c *Cluster) onAll(f func(p *ConnPool) error) error {
+	var first error
+	for _, n := range append([]*clusterNode{c.primary}, c.replicas...) {
+		_, err := call(n, func(p *ConnPool) (struct{}, error) {
+			return struct{}{}, f(p)
+		})
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,453 @@
+package skytable
+
+import (
+	"context"
+
+	"github.com/No3371/go-skytable/action"
+	"github.com/No3371/go-skytable/protocol"
+	"github.com/No3371/go-skytable/response"
+)
+
+// https://docs.skytable.io/actions/heya
+func (c *Cluster) Heya(ctx context.Context, echo string) error {
+	return c.doPrimary(func(p *ConnPool) error {
+		return p.Heya(ctx, echo)
+	})
+}
+
+// https://docs.skytable.io/actions/auth#login
+func (c *Cluster) AuthLogin(ctx context.Context, authProvider AuthProvider) error {
+	return c.onAll(func(p *ConnPool) error {
+		return p.AuthLogin(ctx, authProvider)
+	})
+}
+
+// https://docs.skytable.io/actions/auth#logout
+func (c *Cluster) AuthLogout(ctx context.Context) error {
+	return c.onAll(func(p *ConnPool) error {
+		return p.AuthLogout(ctx)
+	})
+}
+
+// https://docs.skytable.io/actions/auth#claim
+func (c *Cluster) AuthClaim(ctx context.Context, originKey string) (string, error) {
+	return onPrimary(c, func(p *ConnPool) (string, error) {
+		return p.AuthClaim(ctx, originKey)
+	})
+}
+
+// https://docs.skytable.io/actions/auth#adduser
+func (c *Cluster) AuthAddUser(ctx context.Context, username string) (string, error) {
+	return onPrimary(c, func(p *ConnPool) (string, error) {
+		return p.AuthAddUser(ctx, username)
+	})
+}
+
+// https://docs.skytable.io/actions/auth#deluser
+func (c *Cluster) AuthDelUser(ctx context.Context, username string) error {
+	return c.doPrimary(func(p *ConnPool) error {
+		return p.AuthDelUser(ctx, username)
+	})
+}
+
+// https://docs.skytable.io/actions/auth#restore
+//
+// If provided `originKey` is "", it'll be omitted in the sent command
+func (c *Cluster) AuthRestore(ctx context.Context, originKey string, username string) (string, error) {
+	return onPrimary(c, func(p *ConnPool) (string, error) {
+		return p.AuthRestore(ctx, originKey, username)
+	})
+}
+
+// https://docs.skytable.io/actions/auth#listuser
+func (c *Cluster) AuthListUser(ctx context.Context) (*protocol.TypedArray, error) {
+	return onPrimary(c, func(p *ConnPool) (*protocol.TypedArray, error) {
+		return p.AuthListUser(ctx)
+	})
+}
+
+// https://docs.skytable.io/actions/auth#whoami
+func (c *Cluster) AuthWhoAmI(ctx context.Context) (string, error) {
+	return onPrimary(c, func(p *ConnPool) (string, error) {
+		return p.AuthWhoAmI(ctx)
+	})
+}
+
+// https://docs.skytable.io/actions/exists
+func (c *Cluster) Exists(ctx context.Context, keys []string) (uint64, error) {
+	return onPrimary(c, func(p *ConnPool) (uint64, error) {
+		return p.Exists(ctx, keys)
+	})
+}
+
+// https://docs.skytable.io/actions/del
+func (c *Cluster) Del(ctx context.Context, keys []string) (uint64, error) {
+	return onPrimary(c, func(p *ConnPool) (uint64, error) {
+		return p.Del(ctx, keys)
+	})
+}
+
+// https://docs.skytable.io/actions/sdel
+func (c *Cluster) SDel(ctx context.Context, keys []string) error {
+	return c.doPrimary(func(p *ConnPool) error {
+		return p.SDel(ctx, keys)
+	})
+}
+
+// https://docs.skytable.io/actions/get
+func (c *Cluster) Get(ctx context.Context, key string) (response.ResponseEntry, error) {
+	return onReplica(c, func(p *ConnPool) (response.ResponseEntry, error) {
+		return p.Get(ctx, key)
+	})
+}
+
+// a strict version of [Get] that only success if the value is stored as String in Skytable.
+func (c *Cluster) GetString(ctx context.Context, key string) (string, error) {
+	return onReplica(c, func(p *ConnPool) (string, error) {
+		return p.GetString(ctx, key)
+	})
+}
+
+// a strict version of [Get] that only success if the value is stored as BinaryString in Skytable.
+func (c *Cluster) GetBytes(ctx context.Context, key string) ([]byte, error) {
+	return onReplica(c, func(p *ConnPool) ([]byte, error) {
+		return p.GetBytes(ctx, key)
+	})
+}
+
+// https://docs.skytable.io/actions/mget
+func (c *Cluster) MGet(ctx context.Context, keys []string) (*protocol.TypedArray, error) {
+	return onReplica(c, func(p *ConnPool) (*protocol.TypedArray, error) {
+		return p.MGet(ctx, keys)
+	})
+}
+
+// https://docs.skytable.io/actions/pop
+func (c *Cluster) Pop(ctx context.Context, key string) (response.ResponseEntry, error) {
+	return onPrimary(c, func(p *ConnPool) (response.ResponseEntry, error) {
+		return p.Pop(ctx, key)
+	})
+}
+
+// https://docs.skytable.io/actions/mpop
+func (c *Cluster) MPop(ctx context.Context, keys []string) (*protocol.TypedArray, error) {
+	return onPrimary(c, func(p *ConnPool) (*protocol.TypedArray, error) {
+		return p.MPop(ctx, keys)
+	})
+}
+
+// https://docs.skytable.io/actions/set
+func (c *Cluster) Set(ctx context.Context, key string, value any) error {
+	return c.doPrimary(func(p *ConnPool) error {
+		return p.Set(ctx, key, value)
+	})
+}
+
+// https://docs.skytable.io/actions/mset
+func (c *Cluster) MSetB(ctx context.Context, keys []string, values []any) (uint64, error) {
+	return onPrimary(c, func(p *ConnPool) (uint64, error) {
+		return p.MSetB(ctx, keys, values)
+	})
+}
+
+// https://docs.skytable.io/actions/mset
+func (c *Cluster) MSet(ctx context.Context, entries []action.KVPair) (uint64, error) {
+	return onPrimary(c, func(p *ConnPool) (uint64, error) {
+		return p.MSet(ctx, entries)
+	})
+}
+
+// https://docs.skytable.io/actions/sset
+func (c *Cluster) SSet(ctx context.Context, entries []action.KVPair) error {
+	return c.doPrimary(func(p *ConnPool) error {
+		return p.SSet(ctx, entries)
+	})
+}
+
+func (c *Cluster) USet(ctx context.Context, entries ...action.KVPair) (uint64, error) {
+	return onPrimary(c, func(p *ConnPool) (uint64, error) {
+		return p.USet(ctx, entries...)
+	})
+}
+
+// https://docs.skytable.io/actions/update
+func (c *Cluster) Update(ctx context.Context, key string, value any) error {
+	return c.doPrimary(func(p *ConnPool) error {
+		return p.Update(ctx, key, value)
+	})
+}
+
+// https://docs.skytable.io/actions/mupdate
+func (c *Cluster) MUpdate(ctx context.Context, entries []action.KVPair) (uint64, error) {
+	return onPrimary(c, func(p *ConnPool) (uint64, error) {
+		return p.MUpdate(ctx, entries)
+	})
+}
+
+// https://docs.skytable.io/actions/supdate
+func (c *Cluster) SUpdate(ctx context.Context, entries []action.KVPair) error {
+	return c.doPrimary(func(p *ConnPool) error {
+		return p.SUpdate(ctx, entries)
+	})
+}
+
+// https://docs.skytable.io/actions/lget#lget
+func (c *Cluster) LGet(ctx context.Context, listName string) (*protocol.TypedArray, error) {
+	return onReplica(c, func(p *ConnPool) (*protocol.TypedArray, error) {
+		return p.LGet(ctx, listName)
+	})
+}
+
+// https://docs.skytable.io/actions/lget#limit
+func (c *Cluster) LGetLimit(ctx context.Context, listName string, limit uint64) (*protocol.TypedArray, error) {
+	return onReplica(c, func(p *ConnPool) (*protocol.TypedArray, error) {
+		return p.LGetLimit(ctx, listName, limit)
+	})
+}
+
+// https://docs.skytable.io/actions/lget#len
+func (c *Cluster) LGetLen(ctx context.Context, listName string) (uint64, error) {
+	return onReplica(c, func(p *ConnPool) (uint64, error) {
+		return p.LGetLen(ctx, listName)
+	})
+}
+
+// https://docs.skytable.io/actions/lget#valueat
+func (c *Cluster) LGetValueAt(ctx context.Context, listName string, index uint64) (response.ResponseEntry, error) {
+	return onReplica(c, func(p *ConnPool) (response.ResponseEntry, error) {
+		return p.LGetValueAt(ctx, listName, index)
+	})
+}
+
+// https://docs.skytable.io/actions/lget#first
+func (c *Cluster) LGetFirst(ctx context.Context, listName string) (response.ResponseEntry, error) {
+	return onReplica(c, func(p *ConnPool) (response.ResponseEntry, error) {
+		return p.LGetFirst(ctx, listName)
+	})
+}
+
+// https://docs.skytable.io/actions/lget#last
+func (c *Cluster) LGetLast(ctx context.Context, listName string) (response.ResponseEntry, error) {
+	return onReplica(c, func(p *ConnPool) (response.ResponseEntry, error) {
+		return p.LGetLast(ctx, listName)
+	})
+}
+
+// https://docs.skytable.io/actions/lget#range
+//
+// If provided `to` is 0, it's omitted in the sent command.
+func (c *Cluster) LGetRange(ctx context.Context, listName string, from uint64, to uint64) (*protocol.TypedArray, error) {
+	return onReplica(c, func(p *ConnPool) (*protocol.TypedArray, error) {
+		return p.LGetRange(ctx, listName, from, to)
+	})
+}
+
+// https://docs.skytable.io/actions/lmod#push
+func (c *Cluster) LModPush(ctx context.Context, listName string, elements []any) error {
+	return c.doPrimary(func(p *ConnPool) error {
+		return p.LModPush(ctx, listName, elements)
+	})
+}
+
+// https://docs.skytable.io/actions/lmod#insert
+func (c *Cluster) LModInsert(ctx context.Context, listName string, index uint64, element any) error {
+	return c.doPrimary(func(p *ConnPool) error {
+		return p.LModInsert(ctx, listName, index, element)
+	})
+}
+
+// https://docs.skytable.io/actions/lmod#pop
+func (c *Cluster) LModPop(ctx context.Context, listName string) (response.ResponseEntry, error) {
+	return onPrimary(c, func(p *ConnPool) (response.ResponseEntry, error) {
+		return p.LModPop(ctx, listName)
+	})
+}
+
+// https://docs.skytable.io/actions/lmod#pop
+func (c *Cluster) LModPopIndex(ctx context.Context, listName string, index uint64) (response.ResponseEntry, error) {
+	return onPrimary(c, func(p *ConnPool) (response.ResponseEntry, error) {
+		return p.LModPopIndex(ctx, listName, index)
+	})
+}
+
+// https://docs.skytable.io/actions/lmod#remove
+func (c *Cluster) LModRemove(ctx context.Context, listName string, index uint64) error {
+	return c.doPrimary(func(p *ConnPool) error {
+		return p.LModRemove(ctx, listName, index)
+	})
+}
+
+// https://docs.skytable.io/actions/lmod#clear
+func (c *Cluster) LModClear(ctx context.Context, listName string) error {
+	return c.doPrimary(func(p *ConnPool) error {
+		return p.LModClear(ctx, listName)
+	})
+}
+
+// https://docs.skytable.io/actions/lset
+//
+// If `elements` is nil, it's omitted in the sent command.`
+func (c *Cluster) LSet(ctx context.Context, listName string, elements []any) error {
+	return c.doPrimary(func(p *ConnPool) error {
+		return p.LSet(ctx, listName, elements)
+	})
+}
+
+func (c *Cluster) Exec(packet *QueryPacket) ([]response.ResponseEntry, error) {
+	return onPrimary(c, func(p *ConnPool) ([]response.ResponseEntry, error) {
+		return p.Exec(packet)
+	})
+}
+
+func (c *Cluster) ExecSingleActionPacketRaw(segments ...any) (response.ResponseEntry, error) {
+	return onPrimary(c, func(p *ConnPool) (response.ResponseEntry, error) {
+		return p.ExecSingleActionPacketRaw(segments...)
+	})
+}
+
+// https://docs.skytable.io/ddl/#use
+//
+// “USE KEYSPACE” and “USE TABLE” are unified into “USE”.
+func (c *Cluster) Use(ctx context.Context, path string) error {
+	return c.onAll(func(p *ConnPool) error {
+		return p.Use(ctx, path)
+	})
+}
+
+// https://docs.skytable.io/ddl/#inspect
+func (c *Cluster) InspectKeyspaces(ctx context.Context) (*protocol.TypedArray, error) {
+	return onPrimary(c, func(p *ConnPool) (*protocol.TypedArray, error) {
+		return p.InspectKeyspaces(ctx)
+	})
+}
+
+// https://docs.skytable.io/ddl/#keyspaces
+func (c *Cluster) CreateKeyspace(ctx context.Context, name string) error {
+	return c.doPrimary(func(p *ConnPool) error {
+		return p.CreateKeyspace(ctx, name)
+	})
+}
+
+// https://docs.skytable.io/ddl/#keyspaces-1
+func (c *Cluster) DropKeyspace(ctx context.Context, name string) error {
+	return c.doPrimary(func(p *ConnPool) error {
+		return p.DropKeyspace(ctx, name)
+	})
+}
+
+// https://docs.skytable.io/ddl/#keyspaces-2
+//
+// If name is "", inspect the current keyspace
+func (c *Cluster) InspectKeyspace(ctx context.Context, name string) (*protocol.TypedArray, error) {
+	return onPrimary(c, func(p *ConnPool) (*protocol.TypedArray, error) {
+		return p.InspectKeyspace(ctx, name)
+	})
+}
+
+// https://docs.skytable.io/ddl/#tables
+func (c *Cluster) CreateTable(ctx context.Context, path string, modelDesc any) error {
+	return c.doPrimary(func(p *ConnPool) error {
+		return p.CreateTable(ctx, path, modelDesc)
+	})
+}
+
+// https://docs.skytable.io/ddl/#tables-1
+func (c *Cluster) DropTable(ctx context.Context, path string) error {
+	return c.doPrimary(func(p *ConnPool) error {
+		return p.DropTable(ctx, path)
+	})
+}
+
+// https://docs.skytable.io/ddl/#tables-2
+//
+// If path is "", inspect the current table
+func (c *Cluster) InspectTable(ctx context.Context, path string) (protocol.ModelDescription, error) {
+	return onPrimary(c, func(p *ConnPool) (protocol.ModelDescription, error) {
+		return p.InspectTable(ctx, path)
+	})
+}
+
+// https://docs.skytable.io/actions/sys#info
+func (c *Cluster) SysInfoVersion(ctx context.Context) (string, error) {
+	return onPrimary(c, func(p *ConnPool) (string, error) {
+		return p.SysInfoVersion(ctx)
+	})
+}
+
+// https://docs.skytable.io/actions/sys#info
+func (c *Cluster) SysInfoProtocol(ctx context.Context) (string, error) {
+	return onPrimary(c, func(p *ConnPool) (string, error) {
+		return p.SysInfoProtocol(ctx)
+	})
+}
+
+// https://docs.skytable.io/actions/sys#info
+func (c *Cluster) SysInfoProtoVer(ctx context.Context) (float32, error) {
+	return onPrimary(c, func(p *ConnPool) (float32, error) {
+		return p.SysInfoProtoVer(ctx)
+	})
+}
+
+// https://docs.skytable.io/actions/sys#metric
+//
+// Returns true if "good", false when "critical"
+func (c *Cluster) SysMetricHealth(ctx context.Context) (bool, error) {
+	return onPrimary(c, func(p *ConnPool) (bool, error) {
+		return p.SysMetricHealth(ctx)
+	})
+}
+
+// https://docs.skytable.io/actions/sys#metric
+func (c *Cluster) SysMetricStorage(ctx context.Context) (uint64, error) {
+	return onPrimary(c, func(p *ConnPool) (uint64, error) {
+		return p.SysMetricStorage(ctx)
+	})
+}
+
+// https://docs.skytable.io/actions/mksnap
+//
+// If name is "", it will only send "MKSNAP"
+func (c *Cluster) MKSnap(ctx context.Context, name string) error {
+	return c.doPrimary(func(p *ConnPool) error {
+		return p.MKSnap(ctx, name)
+	})
+}
+
+// https://docs.skytable.io/actions/whereami
+func (c *Cluster) WhereAmI(ctx context.Context) (string, error) {
+	return onPrimary(c, func(p *ConnPool) (string, error) {
+		return p.WhereAmI(ctx)
+	})
+}
+
+// https://docs.skytable.io/actions/dbsize
+//
+// If entity is "", check the current table
+func (c *Cluster) DBSize(ctx context.Context, entity string) (uint64, error) {
+	return onPrimary(c, func(p *ConnPool) (uint64, error) {
+		return p.DBSize(ctx, entity)
+	})
+}
+
+// https://docs.skytable.io/actions/keylen
+func (c *Cluster) KeyLen(ctx context.Context, key string) (uint64, error) {
+	return onPrimary(c, func(p *ConnPool) (uint64, error) {
+		return p.KeyLen(ctx, key)
+	})
+}
+
+// https://docs.skytable.io/actions/flushdb
+//
+// If entity is "", flush the current table
+func (c *Cluster) FlushDB(ctx context.Context, entity string) error {
+	return c.doPrimary(func(p *ConnPool) error {
+		return p.FlushDB(ctx, entity)
+	})
+}
+
+// https://docs.skytable.io/actions/lskeys
+func (c *Cluster) LSKeys(ctx context.Context, entity string, limit uint64) (*protocol.TypedArray, error) {
+	return onPrimary(c, func(p *ConnPool) (*protocol.TypedArray, error) {
+		return p.LSKeys(ctx, entity, limit)
+	})
+}
@@ -0,0 +1,126 @@
+package skytable_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/protocol"
+	"github.com/No3371/go-skytable/skytabletest"
+)
+
+func newTestCluster(t *testing.T, replicas int, opts skytable.ClusterOptions) (*skytable.Cluster, *skytabletest.Server, []*skytabletest.Server) {
+	primary, err := skytabletest.NewServer(skytabletest.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { primary.Close() })
+
+	var replicaSrvs []*skytabletest.Server
+	var replicaPools []*skytable.ConnPool
+	for i := 0; i < replicas; i++ {
+		srv, err := skytabletest.NewServer(skytabletest.Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { srv.Close() })
+
+		replicaSrvs = append(replicaSrvs, srv)
+		replicaPools = append(replicaPools, skytable.NewConnPool(srv.Addr(), skytable.DefaultConnPoolOptions))
+	}
+
+	c := skytable.NewCluster(skytable.NewConnPool(primary.Addr(), skytable.DefaultConnPoolOptions), replicaPools, opts)
+	t.Cleanup(c.Close)
+
+	return c, primary, replicaSrvs
+}
+
+func TestCluster_Routing(t *testing.T) {
+	ctx := context.Background()
+	var st skytable.Skytable
+	c, _, _ := newTestCluster(t, 2, skytable.DefaultClusterOptions)
+	st = c
+
+	// Writes go to the primary only
+	if err := st.Set(ctx, "k", "primary"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Primary().GetBytes(ctx, "k"); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, r := range c.Replicas() {
+		if err := r.Set(ctx, "k", "replica"); err != nil {
+			t.Fatalf("replica %d: %s", i, err)
+		}
+	}
+
+	// Reads go to the replicas
+	for i := 0; i < 4; i++ {
+		v, err := st.GetBytes(ctx, "k")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(v) != "replica" {
+			t.Fatalf("expecting the read to be served by a replica but got %s", v)
+		}
+	}
+
+	c.Close() // Closed again by the cleanup
+}
+
+func TestCluster_ReadFailover(t *testing.T) {
+	ctx := context.Background()
+	c, _, replicas := newTestCluster(t, 2, skytable.ClusterOptions{Selection: skytable.SelectLeastBusy})
+
+	if err := c.Set(ctx, "k", "primary"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Replicas()[1].Set(ctx, "k", "replica"); err != nil {
+		t.Fatal(err)
+	}
+	// Open conns on the first replica, then take it down
+	c.Replicas()[0].Heya(ctx, "")
+	replicas[0].Close()
+
+	for i := 0; i < 4; i++ {
+		v, err := c.GetBytes(ctx, "k")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(v) != "replica" {
+			t.Fatalf("expecting the read to fail over to the second replica but got %s", v)
+		}
+	}
+
+	// Then the primary serves the reads
+	replicas[1].Close()
+	v, err := c.GetBytes(ctx, "k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(v) != "primary" {
+		t.Fatalf("expecting the read to fail over to the primary but got %s", v)
+	}
+}
+
+func TestCluster_PrimaryDown(t *testing.T) {
+	ctx := context.Background()
+	c, primary, _ := newTestCluster(t, 1, skytable.DefaultClusterOptions)
+
+	c.Primary().Heya(ctx, "")
+	primary.Close()
+
+	if err := c.Set(ctx, "k", "v"); err == nil {
+		t.Fatal("expecting failure")
+	}
+	if err := c.Set(ctx, "k", "v"); !errors.Is(err, skytable.ErrNoHealthyNode) {
+		t.Fatalf("expecting ErrNoHealthyNode but got %v", err)
+	}
+
+	// Reads still work
+	if _, err := c.Get(ctx, "k"); err != nil && !errors.Is(err, protocol.ErrCodeNil) {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/action"
+	"github.com/No3371/go-skytable/protocol"
+)
+
+type op byte
+
+const (
+	opGet op = iota
+	opSet
+	opUpdate
+	opMGet
+	opLModPush
+	opCount
+)
+
+var opNames = [opCount]string{"get", "set", "update", "mget", "lmodpush"}
+
+func (o op) String() string {
+	return opNames[o]
+}
+
+type weightedOp struct {
+	op     op
+	weight int
+}
+
+// mix is the share of each action in the workload.
+type mix struct {
+	ops   []weightedOp
+	total int
+}
+
+// parseMix parses a mix like "get=80,set=15,mget=5".
+func parseMix(s string) (mix, error) {
+	var m mix
+	seen := make(map[op]bool)
+
+	for _, part := range strings.Split(s, ",") {
+		name, weight, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			return mix{}, fmt.Errorf("invalid mix entry: %s", part)
+		}
+
+		o := op(0)
+		for o < opCount && opNames[o] != strings.ToLower(name) {
+			o++
+		}
+		if o == opCount {
+			return mix{}, fmt.Errorf("unknown action in mix: %s", name)
+		}
+		if seen[o] {
+			return mix{}, fmt.Errorf("duplicated action in mix: %s", name)
+		}
+		seen[o] = true
+
+		w, err := strconv.Atoi(weight)
+		if err != nil || w < 0 {
+			return mix{}, fmt.Errorf("invalid weight of %s: %s", name, weight)
+		}
+		if w > 0 {
+			m.ops = append(m.ops, weightedOp{o, w})
+			m.total += w
+		}
+	}
+
+	if m.total == 0 {
+		return mix{}, errors.New("empty mix")
+	}
+	return m, nil
+}
+
+func (m mix) pick(r *rand.Rand) op {
+	n := r.Intn(m.total)
+	for _, wo := range m.ops {
+		if n < wo.weight {
+			return wo.op
+		}
+		n -= wo.weight
+	}
+	return m.ops[len(m.ops)-1].op
+}
+
+func (m mix) has(o op) bool {
+	for _, wo := range m.ops {
+		if wo.op == o {
+			return true
+		}
+	}
+	return false
+}
+
+type config struct {
+	mix          mix
+	keys         int // Keys populated for get, update and mget
+	lists        int // Lists populated for lmodpush
+	valueSize    int
+	valueSizeMax int // Sizes are picked between valueSize and valueSizeMax, valueSize only if lower
+	mgetKeys     int // Keys per MGET
+	pipeline     int // Actions per packet
+	concurrency  int
+	duration     time.Duration
+}
+
+// populate sets the keys and the lists the workload reads and updates, keeping the existing ones.
+// `kv` and `lists` must be using their tables, `lists` is ignored if nil.
+func populate(ctx context.Context, kv, lists skytable.Skytable, cfg config) error {
+	r := rand.New(rand.NewSource(1))
+
+	batch := make([]action.KVPair, 0, 1000)
+	for i := 0; i < cfg.keys; i++ {
+		batch = append(batch, action.KVPair{K: key(i), V: value(r, cfg)})
+		if len(batch) == cap(batch) || i == cfg.keys-1 {
+			_, err := kv.MSet(ctx, batch)
+			if err != nil {
+				return fmt.Errorf("populating keys: %w", err)
+			}
+			batch = batch[:0]
+		}
+	}
+
+	if lists == nil {
+		return nil
+	}
+	for i := 0; i < cfg.lists; i++ {
+		err := lists.LSet(ctx, list(i), nil)
+		if err != nil && !errors.Is(err, protocol.ErrCodeOverwriteError) {
+			return fmt.Errorf("populating lists: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func key(i int) string  { return "k" + strconv.Itoa(i) }
+func list(i int) string { return "l" + strconv.Itoa(i) }
+
+func value(r *rand.Rand, cfg config) []byte {
+	n := cfg.valueSize
+	if cfg.valueSizeMax > n {
+		n += r.Intn(cfg.valueSizeMax - n + 1)
+	}
+
+	v := make([]byte, n)
+	r.Read(v)
+	return v
+}
+
+type opStats struct {
+	packets   int
+	actions   int
+	errors    int
+	latencies []time.Duration // Of each packet
+}
+
+func (s *opStats) merge(o *opStats) {
+	s.packets += o.packets
+	s.actions += o.actions
+	s.errors += o.errors
+	s.latencies = append(s.latencies, o.latencies...)
+}
+
+// run drives the workload for cfg.duration, each packet holding cfg.pipeline actions of the same kind.
+func run(ctx context.Context, kv, lists skytable.Skytable, cfg config) *report {
+	ctx, cancel := context.WithTimeout(ctx, cfg.duration)
+	defer cancel()
+
+	stats := make([][opCount]opStats, cfg.concurrency)
+
+	// SET fails on existing keys, so every run sets keys of its own
+	setPrefix := "s" + strconv.FormatInt(time.Now().UnixNano(), 36) + "-"
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for w := 0; w < cfg.concurrency; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			work(ctx, w, setPrefix, kv, lists, cfg, &stats[w])
+		}(w)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	var merged [opCount]opStats
+	for w := range stats {
+		for o := range merged {
+			merged[o].merge(&stats[w][o])
+		}
+	}
+
+	return newReport(merged, elapsed)
+}
+
+func work(ctx context.Context, w int, setPrefix string, kv, lists skytable.Skytable, cfg config, stats *[opCount]opStats) {
+	r := rand.New(rand.NewSource(time.Now().UnixNano() + int64(w)))
+	seq := 0
+
+	for ctx.Err() == nil {
+		o := cfg.mix.pick(r)
+
+		actions := make([]skytable.Action, cfg.pipeline)
+		for i := range actions {
+			switch o {
+			case opGet:
+				actions[i] = action.Get{Key: key(r.Intn(cfg.keys))}
+			case opSet:
+				seq++
+				actions[i] = action.Set{Key: fmt.Sprintf("%s%d-%d", setPrefix, w, seq), Value: value(r, cfg)}
+			case opUpdate:
+				actions[i] = action.Update{Key: key(r.Intn(cfg.keys)), Value: value(r, cfg)}
+			case opMGet:
+				keys := make([]string, cfg.mgetKeys)
+				for j := range keys {
+					keys[j] = key(r.Intn(cfg.keys))
+				}
+				actions[i] = action.MGet{Keys: keys}
+			case opLModPush:
+				actions[i] = action.LModPush{ListName: list(r.Intn(cfg.lists)), Elements: []any{value(r, cfg)}}
+			}
+		}
+
+		db := kv
+		if o == opLModPush {
+			db = lists
+		}
+
+		begin := time.Now()
+		resps, err := db.Exec(skytable.NewQueryPacketContext(ctx, actions))
+		latency := time.Since(begin)
+		if ctx.Err() != nil {
+			// Cut by the end of the run
+			return
+		}
+
+		s := &stats[o]
+		s.packets++
+		s.actions += len(actions)
+		s.latencies = append(s.latencies, latency)
+		if err != nil {
+			s.errors += len(actions)
+			continue
+		}
+		for _, resp := range resps {
+			code, isCode := resp.Value.(protocol.ResponseCode)
+			if resp.Err != nil || isCode && code != protocol.RespOkay && code != protocol.RespNil {
+				s.errors++
+			}
+		}
+	}
+}
+
+type opReport struct {
+	Op         string
+	Packets    int
+	Actions    int
+	Errors     int
+	Throughput float64 // Actions per second
+	P50        time.Duration
+	P99        time.Duration
+	P999       time.Duration
+	Max        time.Duration
+}
+
+type report struct {
+	Duration time.Duration
+	Ops      []opReport
+	Total    opReport
+}
+
+func newReport(stats [opCount]opStats, elapsed time.Duration) *report {
+	rep := &report{Duration: elapsed}
+
+	var total opStats
+	for o := range stats {
+		if stats[o].packets == 0 {
+			continue
+		}
+		rep.Ops = append(rep.Ops, newOpReport(op(o).String(), &stats[o], elapsed))
+		total.merge(&stats[o])
+	}
+	rep.Total = newOpReport("total", &total, elapsed)
+
+	return rep
+}
+
+func newOpReport(name string, s *opStats, elapsed time.Duration) opReport {
+	sort.Slice(s.latencies, func(i, j int) bool { return s.latencies[i] < s.latencies[j] })
+
+	r := opReport{
+		Op:         name,
+		Packets:    s.packets,
+		Actions:    s.actions,
+		Errors:     s.errors,
+		Throughput: float64(s.actions) / elapsed.Seconds(),
+		P50:        percentile(s.latencies, 0.5),
+		P99:        percentile(s.latencies, 0.99),
+		P999:       percentile(s.latencies, 0.999),
+	}
+	if len(s.latencies) > 0 {
+		r.Max = s.latencies[len(s.latencies)-1]
+	}
+	return r
+}
+
+// percentile returns the q-th quantile of the sorted durations, by the nearest-rank method.
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(q * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
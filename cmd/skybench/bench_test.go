@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/skytabletest"
+)
+
+func TestParseMix(t *testing.T) {
+	m, err := parseMix("get=80, SET=15,mget=5,lmodpush=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.total != 100 || len(m.ops) != 3 || m.has(opLModPush) {
+		t.Errorf("parseMix() = %+v", m)
+	}
+
+	for _, s := range []string{"", "get", "get=x", "get=-1", "nope=1", "get=1,get=2", "get=0"} {
+		if _, err := parseMix(s); err == nil {
+			t.Errorf("parseMix(%q) succeeded, want an error", s)
+		}
+	}
+}
+
+func TestMix_Pick(t *testing.T) {
+	m, _ := parseMix("get=3,set=1")
+	r := rand.New(rand.NewSource(1))
+
+	var counts [opCount]int
+	for i := 0; i < 4000; i++ {
+		counts[m.pick(r)]++
+	}
+	if counts[opGet] < 2800 || counts[opGet] > 3200 || counts[opGet]+counts[opSet] != 4000 {
+		t.Errorf("picked %v", counts)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 1000)
+	for i := range sorted {
+		sorted[i] = time.Duration(i + 1)
+	}
+
+	for q, want := range map[float64]time.Duration{0: 1, 0.5: 500, 0.99: 990, 0.999: 999, 1: 1000} {
+		if got := percentile(sorted, q); got != want {
+			t.Errorf("percentile(%v) = %d, want %d", q, got, want)
+		}
+	}
+	if percentile(nil, 0.5) != 0 {
+		t.Error("percentile(nil) != 0")
+	}
+}
+
+func TestBench(t *testing.T) {
+	srv, err := skytabletest.NewServer(skytabletest.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+
+	m, _ := parseMix("get=1,set=1,update=1,mget=1,lmodpush=1")
+	rep, err := bench(srv.Addr(), nil, "default:bench", "default:bench_lists", false, config{
+		mix:          m,
+		keys:         100,
+		lists:        10,
+		valueSize:    8,
+		valueSizeMax: 32,
+		mgetKeys:     5,
+		pipeline:     3,
+		concurrency:  4,
+		duration:     200 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rep.Ops) != 5 {
+		t.Errorf("report of %d actions, want 5", len(rep.Ops))
+	}
+	for _, r := range append(rep.Ops, rep.Total) {
+		if r.Packets == 0 || r.Actions != 3*r.Packets || r.Errors != 0 || r.P50 <= 0 || r.P50 > r.P99 || r.P999 > r.Max {
+			t.Errorf("%+v", r)
+		}
+	}
+
+	c, err := skytable.NewConn(srv.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	tables, err := c.InspectKeyspace(context.Background(), "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables.Elements) != 1 {
+		t.Errorf("tables left: %v, want only default", tables.Elements)
+	}
+}
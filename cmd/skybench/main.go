@@ -0,0 +1,205 @@
+// skybench drives a Skytable instance through a ConnPool, reporting the throughput and the latencies.
+//
+// Usage:
+//
+//	skybench -addr 127.0.0.1:2003 -mix get=80,set=15,mget=5 -concurrency 32 -pipeline 4 -duration 30s
+//
+// Each packet holds -pipeline actions of the same kind, the latencies are measured per packet.
+// The tables are created if they do not exist, and dropped at the end unless -keep is set.
+// The token of -user is read from the SKYTABLE_TOKEN environment variable.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/protocol"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:2003", "the address of the Skytable instance")
+	user := flag.String("user", "", "the user to log in as, with the token in SKYTABLE_TOKEN")
+	table := flag.String("table", "default:skybench", "the keymap(str,binstr) table of get, set, update and mget")
+	listTable := flag.String("list-table", "default:skybench_lists", "the keymap(str,list<binstr>) table of lmodpush")
+	keep := flag.Bool("keep", false, "keep the tables created by skybench")
+	mixFlag := flag.String("mix", "get=80,set=20", "the weights of the actions among get, set, update, mget and lmodpush")
+	keys := flag.Int("keys", 10000, "the keys read by get, update and mget")
+	lists := flag.Int("lists", 100, "the lists lmodpush appends to")
+	valueSize := flag.Int("value-size", 64, "the size of the values in bytes")
+	valueSizeMax := flag.Int("value-size-max", 0, "if greater than -value-size, the sizes are random between both")
+	mgetKeys := flag.Int("mget-keys", 10, "the keys per mget")
+	pipeline := flag.Int("pipeline", 1, "the actions per packet")
+	concurrency := flag.Int("concurrency", 16, "the concurrent workers, and the size of the pool")
+	duration := flag.Duration("duration", 10*time.Second, "the duration of the run")
+	jsonOut := flag.Bool("json", false, "print the report as JSON")
+	flag.Parse()
+
+	log.SetFlags(0)
+	log.SetPrefix("skybench: ")
+
+	m, err := parseMix(*mixFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *keys < 1 || *lists < 1 || *mgetKeys < 1 || *pipeline < 1 || *concurrency < 1 || *valueSize < 0 {
+		log.Fatal("-keys, -lists, -mget-keys, -pipeline and -concurrency must be positive")
+	}
+	cfg := config{
+		mix:          m,
+		keys:         *keys,
+		lists:        *lists,
+		valueSize:    *valueSize,
+		valueSizeMax: *valueSizeMax,
+		mgetKeys:     *mgetKeys,
+		pipeline:     *pipeline,
+		concurrency:  *concurrency,
+		duration:     *duration,
+	}
+
+	remote, err := net.ResolveTCPAddr("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var auth skytable.AuthProvider
+	if *user != "" {
+		auth = func() (string, string, error) {
+			return *user, os.Getenv("SKYTABLE_TOKEN"), nil
+		}
+	}
+
+	rep, err := bench(remote, auth, *table, *listTable, *keep, cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(rep)
+	} else {
+		printReport(os.Stdout, rep)
+	}
+}
+
+// bench prepares the tables, runs the workload then drops the tables it created unless `keep`.
+func bench(remote *net.TCPAddr, auth skytable.AuthProvider, table, listTable string, keep bool, cfg config) (*report, error) {
+	ctx := context.Background()
+
+	admin, err := skytable.NewConnAuth(remote, auth)
+	if err != nil {
+		return nil, err
+	}
+	defer admin.Close()
+
+	var created []string
+	defer func() {
+		if keep {
+			return
+		}
+		for _, t := range created {
+			if err := admin.DropTable(ctx, t); err != nil {
+				log.Printf("failed to drop %s: %v", t, err)
+			}
+		}
+	}()
+
+	newPool := func(path, model string) (*skytable.ConnPool, error) {
+		ok, err := createTable(admin, path, model)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			created = append(created, path)
+		}
+
+		return skytable.NewConnPool(remote, skytable.ConnPoolOptions{
+			Cap:           int64(cfg.concurrency),
+			AuthProvider:  auth,
+			DefaultEntity: path,
+		}), nil
+	}
+
+	kv, err := newPool(table, "keymap(str,binstr)")
+	if err != nil {
+		return nil, err
+	}
+	defer closePool(kv)
+
+	var lists skytable.Skytable
+	if cfg.mix.has(opLModPush) {
+		p, err := newPool(listTable, "keymap(str,list<binstr>)")
+		if err != nil {
+			return nil, err
+		}
+		defer closePool(p)
+		lists = p
+	}
+
+	err = populate(ctx, kv, lists, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return run(ctx, kv, lists, cfg), nil
+}
+
+// createTable creates the table, returning false if it exists already.
+func createTable(c *skytable.Conn, path, model string) (bool, error) {
+	resp, err := c.ExecSingleActionPacketRaw("CREATE", "TABLE", path, model)
+	if err != nil {
+		return false, err
+	}
+
+	var errStr *protocol.ErrorStringResponse
+	switch {
+	case errors.As(resp.Err, &errStr) && errStr.Errstr == protocol.ErrStr_AlreadyExists:
+		return false, nil
+	case resp.Err != nil:
+		return false, fmt.Errorf("failed to create %s: %w", path, resp.Err)
+	case resp.Value != protocol.RespOkay:
+		return false, fmt.Errorf("failed to create %s: %v", path, resp.Value)
+	}
+	return true, nil
+}
+
+// closePool closes the conns of the pool, so the tables in use can be dropped.
+func closePool(p *skytable.ConnPool) {
+	p.DoEachConn(func(c *skytable.Conn) error {
+		c.Close()
+		return nil
+	})
+}
+
+func printReport(w io.Writer, rep *report) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "action\tpackets\tactions\terrors\tactions/s\tp50\tp99\tp999\tmax\t")
+	for _, r := range append(rep.Ops, rep.Total) {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%.0f\t%s\t%s\t%s\t%s\t\n",
+			r.Op, r.Packets, r.Actions, r.Errors, r.Throughput, round(r.P50), round(r.P99), round(r.P999), round(r.Max))
+	}
+	tw.Flush()
+
+	fmt.Fprintf(w, "\n%s, %.0f packets/s\n", rep.Duration.Round(time.Millisecond), float64(rep.Total.Packets)/rep.Duration.Seconds())
+}
+
+func round(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond)
+	default:
+		return d.Round(time.Microsecond)
+	}
+}
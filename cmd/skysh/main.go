@@ -0,0 +1,86 @@
+// skysh is an interactive shell for Skytable.
+//
+// Usage:
+//
+//	skysh -addr 127.0.0.1:2003         # interactive
+//	skysh -addr 127.0.0.1:2003 GET k   # runs a single action
+//	skysh < script.sky                 # runs the actions of the script
+//
+// The token of -user is read from the SKYTABLE_TOKEN environment variable.
+// Type .help in the shell for the syntax.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/No3371/go-skytable"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:2003", "the address of the Skytable instance")
+	user := flag.String("user", "", "the user to log in as, with the token in SKYTABLE_TOKEN")
+	history := flag.String("history", defaultHistory(), "the history file of the interactive mode, none if empty")
+	flag.Parse()
+
+	log.SetFlags(0)
+	log.SetPrefix("skysh: ")
+
+	remote, err := net.ResolveTCPAddr("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var auth skytable.AuthProvider
+	if *user != "" {
+		auth = func() (string, string, error) {
+			return *user, os.Getenv("SKYTABLE_TOKEN"), nil
+		}
+	}
+
+	c, err := skytable.NewConnAuth(remote, auth)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
+
+	s := &shell{
+		conn: c,
+		out:  os.Stdout,
+	}
+
+	if flag.NArg() > 0 {
+		// The arguments are the segments of a single action, as split by the shell
+		err = s.exec([][]string{flag.Args()})
+	} else {
+		if fi, err := os.Stdin.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+			s.interactive = true
+			if *history != "" {
+				if err := s.loadHistory(*history); err != nil {
+					fmt.Fprintf(os.Stderr, "skysh: history disabled: %v\n", err)
+				}
+			}
+			fmt.Printf("Connected to %s, type .help for help\n", *addr)
+		}
+		err = s.run(os.Stdin)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if s.failed && !s.interactive {
+		os.Exit(1)
+	}
+}
+
+func defaultHistory() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".skysh_history")
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var errUnterminatedQuote = errors.New("unterminated quote")
+
+// splitActions splits a line into actions separated by ';', and the actions into segments separated by spaces.
+//
+// Segments can be quoted: single quotes keep their content as is,
+// double quotes interpret the escapes \\, \", \n, \r, \t, \0 and \xHH.
+func splitActions(line string) ([][]string, error) {
+	var actions [][]string
+	var action []string
+	var seg strings.Builder
+	inSeg := false
+
+	endSeg := func() {
+		if inSeg {
+			action = append(action, seg.String())
+			seg.Reset()
+			inSeg = false
+		}
+	}
+	endAction := func() {
+		endSeg()
+		if len(action) > 0 {
+			actions = append(actions, action)
+			action = nil
+		}
+	}
+
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; c {
+		case ' ', '\t':
+			endSeg()
+		case ';':
+			endAction()
+		case '\'':
+			end := strings.IndexByte(line[i+1:], '\'')
+			if end < 0 {
+				return nil, errUnterminatedQuote
+			}
+			seg.WriteString(line[i+1 : i+1+end])
+			inSeg = true
+			i += end + 1
+		case '"':
+			n, err := unquote(line[i+1:], &seg)
+			if err != nil {
+				return nil, err
+			}
+			inSeg = true
+			i += n
+		default:
+			seg.WriteByte(c)
+			inSeg = true
+		}
+	}
+	endAction()
+
+	return actions, nil
+}
+
+// unquote writes the content of a double quoted segment to b, returning the length consumed including the closing quote.
+func unquote(s string, b *strings.Builder) (int, error) {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"':
+			return i + 1, nil
+		case '\\':
+			if i+1 >= len(s) {
+				return 0, errUnterminatedQuote
+			}
+			i++
+			switch e := s[i]; e {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '0':
+				b.WriteByte(0)
+			case 'x':
+				if i+2 >= len(s) {
+					return 0, errors.New("invalid \\x escape")
+				}
+				v, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+				if err != nil {
+					return 0, errors.New("invalid \\x escape")
+				}
+				b.WriteByte(byte(v))
+				i += 2
+			default:
+				b.WriteByte(e)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return 0, errUnterminatedQuote
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/No3371/go-skytable/protocol"
+	"github.com/No3371/go-skytable/response"
+)
+
+// printEntry writes the response with its type, arrays as trees:
+//
+//	(typed array<str>, 2 elements)
+//	  1) (str) "a"
+//	  2) (nil)
+func printEntry(w io.Writer, e response.ResponseEntry, prefix string) {
+	if e.Err != nil {
+		var errStr *protocol.ErrorStringResponse
+		if errors.As(e.Err, &errStr) {
+			fmt.Fprintf(w, "%s(error) %s\n", prefix, errStr.Errstr)
+		} else {
+			fmt.Fprintf(w, "%s(error) %v\n", prefix, e.Err)
+		}
+		return
+	}
+
+	printValue(w, e.Value, prefix, strings.Repeat(" ", len(prefix)))
+}
+
+func printValue(w io.Writer, v any, prefix string, indent string) {
+	switch v := v.(type) {
+	case nil:
+		fmt.Fprintf(w, "%s(nil)\n", prefix)
+	case protocol.ResponseCode:
+		fmt.Fprintf(w, "%s(code) %s\n", prefix, v)
+	case string:
+		fmt.Fprintf(w, "%s(str) %s\n", prefix, strconv.Quote(v))
+	case []byte:
+		fmt.Fprintf(w, "%s(binstr) %q\n", prefix, v)
+	case uint64, int64:
+		fmt.Fprintf(w, "%s(int) %d\n", prefix, v)
+	case float32:
+		fmt.Fprintf(w, "%s(float) %v\n", prefix, v)
+	case *protocol.TypedArray:
+		fmt.Fprintf(w, "%s(%s<%s>, %s)\n", prefix, arrayName(v.ArrayType), simpleTypeName(v.ElementType), count(len(v.Elements)))
+		printElements(w, v.Elements, indent)
+	case *protocol.Array:
+		fmt.Fprintf(w, "%s(%s, %s)\n", prefix, arrayName(v.ArrayType), count(len(v.Elements)))
+		printElements(w, v.Elements, indent)
+	default:
+		fmt.Fprintf(w, "%s(%T) %v\n", prefix, v, v)
+	}
+}
+
+func printElements(w io.Writer, elements []any, indent string) {
+	width := len(strconv.Itoa(len(elements)))
+	for i, e := range elements {
+		p := fmt.Sprintf("%s  %*d) ", indent, width, i+1)
+		printValue(w, e, p, strings.Repeat(" ", len(p)-2))
+	}
+}
+
+func count(n int) string {
+	switch n {
+	case 0:
+		return "empty"
+	case 1:
+		return "1 element"
+	default:
+		return fmt.Sprintf("%d elements", n)
+	}
+}
+
+func simpleTypeName(t protocol.SimpleType) string {
+	switch t {
+	case protocol.SimpleTypeString:
+		return "str"
+	case protocol.SimpleTypeBinaryString:
+		return "binstr"
+	case protocol.SimpleTypeInt, protocol.SimpleTypeIntSigned, protocol.SimpleTypeSmallint, protocol.SimpleTypeSmallintSigned:
+		return "int"
+	case protocol.SimpleTypeFloat:
+		return "float"
+	case protocol.SimpleTypeJson:
+		return "json"
+	case protocol.SimpleTypeResponseCode:
+		return "code"
+	default:
+		return string(rune(t))
+	}
+}
+
+func arrayName(t protocol.CompoundType) string {
+	switch t {
+	case protocol.CompoundTypeFlatArray:
+		return "flat array"
+	case protocol.CompoundTypeAnyArray:
+		return "any array"
+	case protocol.CompoundTypeTypedArray:
+		return "typed array"
+	case protocol.CompoundTypeTypedNonNullArray:
+		return "typed non-null array"
+	default:
+		return "array"
+	}
+}
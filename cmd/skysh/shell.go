@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/protocol"
+	"github.com/No3371/go-skytable/response"
+)
+
+const help = `Actions are sent as typed, for example:
+  SET k v
+  LMOD list PUSH a "b c" 'd'
+  INSPECT KEYSPACES
+
+Segments can be quoted, double quotes interpret \n, \t, \0, \xHH...
+Actions separated by ';', or written between lines "{" and "}", are sent in a single packet.
+
+  .history   list the history, !n runs the n-th line again, !! the last one
+  .help      print this help
+  .quit      exit
+`
+
+type shell struct {
+	conn        *skytable.Conn
+	out         io.Writer
+	interactive bool // Prompts and keeps the history
+
+	location    string     // The current keyspace or table, as reported by WHEREAMI
+	block       [][]string // The actions of the "{ }" block being read
+	inBlock     bool
+	history     []string
+	historyFile io.Writer // The history is appended to it, if not nil
+	failed      bool      // Any action failed
+}
+
+// run executes the lines of `in` until its end or a quit command.
+// It returns an error only if the connection fails.
+func (s *shell) run(in io.Reader) error {
+	if s.interactive {
+		err := s.whereAmI()
+		if err != nil {
+			return err
+		}
+	}
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for {
+		s.prompt()
+		if !scanner.Scan() {
+			break
+		}
+
+		quit, err := s.line(scanner.Text())
+		if err != nil {
+			return err
+		}
+		if quit {
+			return nil
+		}
+	}
+	if s.interactive {
+		fmt.Fprintln(s.out)
+	}
+
+	return scanner.Err()
+}
+
+func (s *shell) prompt() {
+	if !s.interactive {
+		return
+	}
+
+	if s.inBlock {
+		fmt.Fprint(s.out, "... ")
+	} else {
+		fmt.Fprintf(s.out, "skysh %s> ", s.location)
+	}
+}
+
+// line executes a line, returning true if it's a quit command.
+func (s *shell) line(text string) (quit bool, err error) {
+	text = strings.TrimSpace(text)
+
+	if strings.HasPrefix(text, "!") {
+		text, err = s.recall(text)
+		if err != nil {
+			s.fail(err)
+			return false, nil
+		}
+		fmt.Fprintln(s.out, text)
+	}
+	if text != "" && !strings.HasPrefix(text, "#") {
+		s.remember(text)
+	}
+
+	if s.inBlock {
+		if text == "}" {
+			block := s.block
+			s.block, s.inBlock = nil, false
+			return false, s.exec(block)
+		}
+
+		actions, err := splitActions(text)
+		if err != nil {
+			s.fail(err)
+			return false, nil
+		}
+		s.block = append(s.block, actions...)
+		return false, nil
+	}
+
+	switch strings.ToLower(text) {
+	case "":
+		return false, nil
+	case ".quit", ".exit", "quit", "exit":
+		return true, nil
+	case ".help", "help":
+		fmt.Fprint(s.out, help)
+		return false, nil
+	case ".history":
+		for i, h := range s.history {
+			fmt.Fprintf(s.out, "%5d  %s\n", i+1, h)
+		}
+		return false, nil
+	case "{":
+		s.inBlock = true
+		return false, nil
+	}
+	if strings.HasPrefix(text, "#") {
+		return false, nil
+	}
+
+	actions, err := splitActions(text)
+	if err != nil {
+		s.fail(err)
+		return false, nil
+	}
+
+	return false, s.exec(actions)
+}
+
+// recall returns the line of the history referenced by "!!" or "!n".
+func (s *shell) recall(text string) (string, error) {
+	n := len(s.history)
+	if text != "!!" {
+		var err error
+		n, err = strconv.Atoi(text[1:])
+		if err != nil {
+			return "", fmt.Errorf("invalid history reference: %s", text)
+		}
+	}
+	if n < 1 || n > len(s.history) {
+		return "", fmt.Errorf("no such history entry: %s", text)
+	}
+
+	return s.history[n-1], nil
+}
+
+func (s *shell) remember(text string) {
+	if !s.interactive {
+		return
+	}
+
+	s.history = append(s.history, text)
+	if s.historyFile != nil {
+		fmt.Fprintln(s.historyFile, text)
+	}
+}
+
+// exec sends the actions in a single packet and prints the responses.
+func (s *shell) exec(actions [][]string) error {
+	if len(actions) == 0 {
+		return nil
+	}
+
+	packet := make([][]any, len(actions))
+	for i, a := range actions {
+		packet[i] = make([]any, len(a))
+		for j, seg := range a {
+			packet[i][j] = seg
+		}
+	}
+
+	var resps []response.ResponseEntry
+	if len(packet) == 1 {
+		resp, err := s.conn.ExecSingleActionPacketRaw(packet[0]...)
+		if err != nil {
+			return err
+		}
+		resps = []response.ResponseEntry{resp}
+	} else {
+		var err error
+		resps, err = s.conn.ExecActionsPacketRaw(packet...)
+		if err != nil {
+			return err
+		}
+	}
+
+	used := false
+	for i, resp := range resps {
+		prefix := ""
+		if len(resps) > 1 {
+			prefix = fmt.Sprintf("[%d] ", i+1)
+		}
+		printEntry(s.out, resp, prefix)
+
+		code, isCode := resp.Value.(protocol.ResponseCode)
+		if resp.Err != nil || isCode && code != protocol.RespOkay && code != protocol.RespNil {
+			s.failed = true
+		}
+		if i < len(actions) && strings.EqualFold(actions[i][0], "USE") && resp.Err == nil && code == protocol.RespOkay {
+			used = true
+		}
+	}
+
+	if used {
+		return s.whereAmI()
+	}
+	return nil
+}
+
+func (s *shell) whereAmI() error {
+	location, err := s.conn.WhereAmI(context.Background())
+	if err != nil {
+		return err
+	}
+
+	s.location = location
+	return nil
+}
+
+func (s *shell) fail(err error) {
+	s.failed = true
+	fmt.Fprintf(s.out, "(error) %v\n", err)
+}
+
+// loadHistory reads the history file, then opens it to append the new lines.
+func (s *shell) loadHistory(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, l := range strings.Split(string(b), "\n") {
+		if l != "" {
+			s.history = append(s.history, l)
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	s.historyFile = f
+	return nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/skytabletest"
+)
+
+func TestSplitActions(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    [][]string
+		wantErr bool
+	}{
+		{"SET k v", [][]string{{"SET", "k", "v"}}, false},
+		{"  GET   k  ", [][]string{{"GET", "k"}}, false},
+		{`SET k "a b"`, [][]string{{"SET", "k", "a b"}}, false},
+		{`SET k 'a "b"'`, [][]string{{"SET", "k", `a "b"`}}, false},
+		{`SET k "\x00\n\"" ""`, [][]string{{"SET", "k", "\x00\n\"", ""}}, false},
+		{`SET k a"b c"d`, [][]string{{"SET", "k", "ab cd"}}, false},
+		{"SET k v; GET k;; DEL k;", [][]string{{"SET", "k", "v"}, {"GET", "k"}, {"DEL", "k"}}, false},
+		{`SET k "a;b"`, [][]string{{"SET", "k", "a;b"}}, false},
+		{"", nil, false},
+		{`SET k "v`, nil, true},
+		{`SET k 'v`, nil, true},
+		{`SET k "\xZZ"`, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := splitActions(tt.line)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("splitActions(%q) error = %v, wantErr %v", tt.line, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitActions(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func newTestShell(t *testing.T) (*shell, *bytes.Buffer) {
+	srv, err := skytabletest.NewServer(skytabletest.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { srv.Close() })
+
+	c, err := skytable.NewConn(srv.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	out := &bytes.Buffer{}
+	return &shell{conn: c, out: out}, out
+}
+
+func TestShell_Script(t *testing.T) {
+	s, out := newTestShell(t)
+
+	script := `# a comment
+SET k "v 1"
+GET k; GET missing
+CREATE TABLE default:lists keymap(str,list<str>)
+USE default:lists
+{
+LSET l a b
+LGET l
+}
+INSPECT KEYSPACE nope
+`
+	if err := s.run(strings.NewReader(script)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `(code) Okay
+[1] (binstr) "v 1"
+[2] (code) Nil
+(code) Okay
+(code) Okay
+[1] (code) Okay
+[2] (typed array<str>, 2 elements)
+      1) (str) "a"
+      2) (str) "b"
+(error) container-not-found
+`
+	if out.String() != want {
+		t.Errorf("output:\n%s\nwant:\n%s", out.String(), want)
+	}
+	if s.location != "default:lists" {
+		t.Errorf("location = %s, want default:lists", s.location)
+	}
+	if !s.failed {
+		t.Error("failed = false, want true")
+	}
+}
+
+func TestShell_Interactive(t *testing.T) {
+	s, out := newTestShell(t)
+	s.interactive = true
+
+	if err := s.run(strings.NewReader("SET k v\nEXISTS k\n!2\n!9\n.history\nquit\nGET k\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `skysh default:default> (code) Okay
+skysh default:default> (int) 1
+skysh default:default> EXISTS k
+(int) 1
+skysh default:default> (error) no such history entry: !9
+skysh default:default>     1  SET k v
+    2  EXISTS k
+    3  EXISTS k
+    4  .history
+skysh default:default> `
+	if out.String() != want {
+		t.Errorf("output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
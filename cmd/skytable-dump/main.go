@@ -0,0 +1,98 @@
+// skytable-dump exports keyspaces of a Skytable instance as JSON Lines, and imports them back.
+// See skytablex.Dump for the format.
+//
+// Usage:
+//
+//	skytable-dump -addr 127.0.0.1:2003 -keyspaces app > app.jsonl
+//	skytable-dump -addr 127.0.0.1:2003 -restore < app.jsonl
+//
+// The token of -user is read from the SKYTABLE_TOKEN environment variable.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/skytablex"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:2003", "the address of the Skytable instance")
+	user := flag.String("user", "", "the user to log in as, with the token in SKYTABLE_TOKEN")
+	file := flag.String("f", "-", "the dump file, - for stdout or stdin")
+	keyspaces := flag.String("keyspaces", "", "comma separated keyspaces to dump, all but system if empty")
+	restore := flag.Bool("restore", false, "restore the dump instead of dumping")
+	overwrite := flag.Bool("overwrite", false, "replace existing keys when restoring")
+	batch := flag.Int("batch", 100, "keys read or written per query")
+	flag.Parse()
+
+	log.SetFlags(0)
+	log.SetPrefix("skytable-dump: ")
+
+	remote, err := net.ResolveTCPAddr("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var auth skytable.AuthProvider
+	if *user != "" {
+		auth = func() (string, string, error) {
+			return *user, os.Getenv("SKYTABLE_TOKEN"), nil
+		}
+	}
+
+	c, err := skytable.NewConnAuth(remote, auth)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
+
+	ctx := context.Background()
+
+	if *restore {
+		var r io.Reader = os.Stdin
+		if *file != "-" {
+			f, err := os.Open(*file)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer f.Close()
+			r = f
+		}
+
+		stats, err := skytablex.Restore(ctx, c, r, skytablex.RestoreOptions{Overwrite: *overwrite, BatchSize: *batch})
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(os.Stderr, "restored %d keyspaces, %d tables, %d entries (%d skipped)\n", stats.Keyspaces, stats.Tables, stats.Entries, stats.Skipped)
+		return
+	}
+
+	var w io.Writer = os.Stdout
+	if *file != "-" {
+		f, err := os.Create(*file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	opts := skytablex.DumpOptions{BatchSize: *batch}
+	if *keyspaces != "" {
+		opts.Keyspaces = strings.Split(*keyspaces, ",")
+	}
+
+	stats, err := skytablex.Dump(ctx, c, w, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Fprintf(os.Stderr, "dumped %d keyspaces, %d tables, %d entries\n", stats.Keyspaces, stats.Tables, stats.Entries)
+}
@@ -21,6 +21,12 @@ type Conn struct {
 	respReader *response.ResponseReader
 
 	autoReconnect bool
+	retry         *RetryPolicy
+	responded     bool // A response was read since the conn was rented from its ConnPool
+
+	// Restored on reconnection
+	authProvider AuthProvider
+	entity       string
 
 	closed chan struct{}
 	err    error
@@ -34,7 +40,12 @@ func (c Conn) UsedAt () time.Time {
 	return c.usedAt
 }
 
+// Close closes the conn, it's a no-op if the conn is already closed (e.g. due to an error).
 func (c *Conn) Close() {
+	if c.isClosed() {
+		return
+	}
+
 	close(c.closed)
 	c.netConn.Close()
 }
@@ -71,6 +82,31 @@ func (c *Conn) reconnect () error {
 	c.err = nil
 	c.netConn = nc
 
+	err = c.restoreSession()
+	if err != nil {
+		if !c.isClosed() {
+			c.errClose(err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// restoreSession validates the protocol version, logs in and USEs the last entity on a reconnected conn.
+func (c *Conn) restoreSession() (err error) {
+	// Retrying is up to the caller of reconnect()
+	retry := c.retry
+	c.retry = nil
+	defer func() { c.retry = retry }()
+
+	if c.authProvider != nil {
+		err = c.AuthLogin(context.Background(), c.authProvider)
+		if err != nil {
+			return fmt.Errorf("conn: failed to auth login: %w", err)
+		}
+	}
+
 	pv, err := c.SysInfoProtocol(context.Background())
 	if err != nil {
 		return fmt.Errorf("conn: failed to get protocol version: %w", err)
@@ -80,16 +116,32 @@ func (c *Conn) reconnect () error {
 		return protocol.ErrProtocolVersion
 	}
 
+	if c.entity != "" {
+		err = c.Use(context.Background(), c.entity)
+		if err != nil {
+			return fmt.Errorf("conn: failed to USE %s: %w", c.entity, err)
+		}
+	}
+
 	return nil
 }
 
+func (c *Conn) isClosed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
+
 func (c *Conn) checkClosed () error {
 	select {
 	case <-c.closed:
 		if c.autoReconnect {
 			err := c.reconnect()
 			if err != nil {
-				return fmt.Errorf("failed to reconnect: %w (previous: %s)", err, c.err)
+				return newUnsentComuError(fmt.Sprintf("failed to reconnect (previous: %s)", c.err), err)
 			}
 			return nil
 		} else {
@@ -194,6 +246,27 @@ func (c *Conn) BuildSingleActionPacketRaw(segs []any) (raw string, err error) {
 	return c.strBuilder.String(), nil
 }
 
+// Allows building a packet of multiple actions, pipelined, like:
+//     c.BuildActionsPacketRaw([][]any{{"SET", "X", 100}, {"GET", "X"}})
+//
+// The segments are formatted like [Conn.BuildSingleActionPacketRaw].
+func (c *Conn) BuildActionsPacketRaw(actions [][]any) (raw string, err error) {
+	c.strBuilder.Reset()
+	_, err = fmt.Fprintf(c.strBuilder, "*%d\n", len(actions))
+	if err != nil {
+		return "", err
+	}
+
+	for _, segs := range actions {
+		err = c.appendSingleActionRaw(segs)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return c.strBuilder.String(), nil
+}
+
 func (c *Conn) appendSingleActionRaw(segs []any) (err error) {
 	_, err = fmt.Fprintf(c.strBuilder, "~%d\n", len(segs))
 	if err != nil {
@@ -216,10 +289,10 @@ func (c *Conn) ExecRaw(query string) (*RawResponsePacket, error) {
 		return nil, err
 	}
 
-	_, err := c.netConn.Write([]byte(query))
+	n, err := c.netConn.Write([]byte(query))
 	if err != nil {
 		c.errClose(err)
-		return nil, NewComuError("failed to write to conn", err)
+		return nil, writeError(n, err)
 	}
 
 	resps, err := c.respReader.Read(c.netConn)
@@ -229,6 +302,7 @@ func (c *Conn) ExecRaw(query string) (*RawResponsePacket, error) {
 	}
 
 	c.usedAt = time.Now()
+	c.responded = true
 
 	return &RawResponsePacket{
 		resps: resps,
@@ -253,10 +327,10 @@ func (c *Conn) ExecQuery(bq BuiltQuery) (*ResponsePacket, error) {
 		return nil, err
 	}
 
-	_, err := c.netConn.Write([]byte(bq.string))
+	n, err := c.netConn.Write([]byte(bq.string))
 	if err != nil {
 		c.errClose(err)
-		return nil, NewComuError("failed to write to conn", err)
+		return nil, writeError(n, err)
 	}
 
 	resps, err := c.respReader.Read(c.netConn)
@@ -272,6 +346,7 @@ func (c *Conn) ExecQuery(bq BuiltQuery) (*ResponsePacket, error) {
 	}
 
 	c.usedAt = time.Now()
+	c.responded = true
 
 	return &ResponsePacket{
 		query: bq.QueryPacket,
@@ -315,7 +390,16 @@ func (c *Conn) BuildQuery(p *QueryPacket) (BuiltQuery, error) {
 	return BuiltQuery{p, c.strBuilder.String()}, nil
 }
 
+// BuildAndExecQuery builds and executes the packet, retrying it if a [RetryPolicy] is set (see [Conn.SetRetryPolicy]).
 func (c *Conn) BuildAndExecQuery(p *QueryPacket) (*ResponsePacket, error) {
+	if c.retry != nil {
+		return c.execWithRetry(p)
+	}
+
+	return c.buildAndExecQuery(p)
+}
+
+func (c *Conn) buildAndExecQuery(p *QueryPacket) (*ResponsePacket, error) {
 	if err := c.checkClosed(); err != nil {
 		return nil, err
 	}
@@ -61,6 +61,7 @@ func (c *Conn) AuthLogin(ctx context.Context, authProvider AuthProvider) error {
 	case protocol.ResponseCode:
 		switch code {
 		case protocol.RespOkay:
+			c.authProvider = authProvider
 			return nil
 		case protocol.RespBadCredentials:
 			return protocol.ErrCodeBadCredentials
@@ -94,6 +95,7 @@ func (c *Conn) AuthLogout(ctx context.Context) error {
 	case protocol.ResponseCode:
 		switch code {
 		case protocol.RespOkay:
+			c.authProvider = nil
 			return nil
 		case protocol.RespBadCredentials:
 			return protocol.ErrCodeBadCredentials
@@ -875,6 +877,25 @@ func (c *Conn) ExecSingleActionPacketRaw(segments ...any) (response.ResponseEntr
 	return rr.resps[0], nil
 }
 
+// Allows executing multiple actions in a single packet easily like:
+//
+//	c.ExecActionsPacketRaw([]any{"SET", "X", 100}, []any{"GET", "X"})
+//
+// The responses are in the order of the actions. The segments are formatted like [Conn.ExecSingleActionPacketRaw].
+func (c *Conn) ExecActionsPacketRaw(actions ...[]any) ([]response.ResponseEntry, error) {
+	raw, err := c.BuildActionsPacketRaw(actions)
+	if err != nil {
+		return nil, err
+	}
+
+	rr, err := c.ExecRaw(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return rr.resps, nil
+}
+
 // https://docs.skytable.io/ddl/#inspect
 func (c *Conn) InspectKeyspaces(ctx context.Context) (*protocol.TypedArray, error) {
 	rp, err := c.BuildAndExecQuery(NewQueryPacket([]Action{action.InspectKeyspaces{}}))
@@ -961,6 +982,7 @@ func (c *Conn) Use(ctx context.Context, path string) error {
 	case protocol.ResponseCode:
 		switch resp {
 		case protocol.RespOkay:
+			c.entity = path
 			return nil
 		case protocol.RespServerError:
 			return protocol.ErrCodeServerError
@@ -1816,6 +1838,8 @@ func (c *Conn) LSet(ctx context.Context, listName string, elements []any) error
 			return nil
 		case protocol.RespNil:
 			return protocol.ErrCodeNil
+		case protocol.RespOverwriteError:
+			return protocol.ErrCodeOverwriteError
 		case protocol.RespServerError:
 			return protocol.ErrCodeServerError
 		default:
@@ -40,6 +40,28 @@ func TestConn_ExecSingleActionPacketRaw(t *testing.T) {
 	}
 }
 
+func TestConn_ExecActionsPacketRaw(t *testing.T) {
+	c, err := NewConnNoAuth()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.ExecActionsPacketRaw([]any{"SET", "Y", 100}, []any{"GET", "Y"}, []any{"DEL", "Y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []any{protocol.RespOkay, []byte("100"), uint64(1)}
+	if len(got) != len(want) {
+		t.Fatalf("Conn.ExecActionsPacketRaw() returned %d responses, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].Err != nil || !reflect.DeepEqual(got[i].Value, want[i]) {
+			t.Errorf("Conn.ExecActionsPacketRaw() #%d = %v, %v, want %v", i, got[i].Value, got[i].Err, want[i])
+		}
+	}
+}
+
 func TestConn_USet(t *testing.T) {
 	c, err := NewConnNoAuth()
 	if err != nil {
@@ -22,12 +22,15 @@ type ConnPool struct {
 	opened int64 // atomic
 	remote       *net.TCPAddr
 	opts ConnPoolOptions
+	breaker *breaker
 }
 
 type ConnPoolOptions struct {
 	Cap          int64 // The maximun of opened Conns at the same time
 	AuthProvider func() (username, token string, err error) // Do not keep auth info in memory
 	DefaultEntity string // "KEYSPACE" or "KEYSPACE:CONTAINER"
+	RetryPolicy *RetryPolicy // Retry queries with the policy on every conn, see [Conn.SetRetryPolicy]
+	Breaker *BreakerOptions // Fail fast when the server is unreachable, see [BreakerOptions]
 }
 
 var DefaultConnPoolOptions = ConnPoolOptions{
@@ -48,6 +51,10 @@ func NewConnPool(remote *net.TCPAddr, opts ConnPoolOptions) *ConnPool {
 		opts: opts,
 	}
 
+	if opts.Breaker != nil {
+		cp.breaker = newBreaker(*opts.Breaker)
+	}
+
 	return cp
 }
 
@@ -56,6 +63,25 @@ func (c *ConnPool) OpenedConns() int64 {
 }
 
 func (c *ConnPool) popConn(dontOpenNew bool) (conn *Conn, err error) {
+	conn, err = c.takeConn(dontOpenNew)
+	if conn != nil {
+		// The responses to opening or probing the conn were already accounted
+		conn.responded = false
+	}
+	return conn, err
+}
+
+func (c *ConnPool) takeConn(dontOpenNew bool) (conn *Conn, err error) {
+	if c.breaker != nil {
+		probe, err := c.breaker.allow()
+		if err != nil {
+			return nil, err
+		}
+		if probe {
+			return c.probe()
+		}
+	}
+
 	if dontOpenNew {
 		return <-c.available, nil
 	}
@@ -65,7 +91,11 @@ func (c *ConnPool) popConn(dontOpenNew bool) (conn *Conn, err error) {
 		return conn, nil
 	default:
 		if atomic.LoadInt64(&c.opened) < c.opts.Cap {
-			return c.openConn()
+			conn, err = c.openConn()
+			if err != nil && c.breaker != nil {
+				c.breaker.failure(err)
+			}
+			return conn, err
 		} else {
 			conn = <-c.available
 			return conn, nil
@@ -77,10 +107,18 @@ func (c *ConnPool) pushConn(conn *Conn) {
 	select {
 	case <-conn.closed:
 		atomic.AddInt64(&c.opened, -1)
+		if c.breaker != nil && isComuFailure(conn.err) {
+			c.breaker.failure(conn.err)
+		}
 		return
 	default:
 	}
 
+	// Only a query completed while rented proves the server is reachable
+	if c.breaker != nil && conn.responded {
+		c.breaker.success()
+	}
+
 	select {
 	case c.available <- conn:
 	default:
@@ -111,6 +149,31 @@ func (c *ConnPool) RentConn (dontOpenNew bool) (conn *Conn, pusher func (), err
 	return conn, pusher, err
 }
 
+// probe opens a new conn, or takes a queued one if the pool is at its cap, and sends HEYA with it, closing the breaker if it succeeds.
+func (c *ConnPool) probe() (conn *Conn, err error) {
+	if atomic.LoadInt64(&c.opened) < c.opts.Cap {
+		conn, err = c.openConn()
+	} else {
+		conn = <-c.available
+	}
+	if err == nil {
+		err = conn.Heya(context.Background(), "")
+		if err != nil {
+			conn.Close()
+			atomic.AddInt64(&c.opened, -1)
+		}
+	}
+
+	if err != nil {
+		c.breaker.failure(err)
+		_, err = c.breaker.allow()
+		return nil, err
+	}
+
+	c.breaker.success()
+	return conn, nil
+}
+
 func (c *ConnPool) openConn() (conn *Conn, err error) {
 	if c.opts.AuthProvider != nil {
 		conn, err = NewConnAuth(c.remote, c.opts.AuthProvider)
@@ -140,6 +203,8 @@ func (c *ConnPool) openConn() (conn *Conn, err error) {
 		}
 	}
 
+	conn.SetRetryPolicy(c.opts.RetryPolicy)
+
 
 	atomic.AddInt64(&c.opened, 1)
 	return conn, nil
@@ -149,7 +214,6 @@ func (c *ConnPool) openConn() (conn *Conn, err error) {
 // If an error is returned, the iteration may be incomplete.
 func (c *ConnPool) DoEachConn(action func (conn *Conn) error) error {
 	t := time.Now()
-	ited := 0
 	conns := make([]*Conn, 0, c.OpenedConns())
 	defer func () {
 		for _, conn := range conns {
@@ -157,12 +221,14 @@ func (c *ConnPool) DoEachConn(action func (conn *Conn) error) error {
 		}
 	} ()
 
-	for ; ited < int(c.OpenedConns()); {
+	// Conns opened after the call are kept aside too, so they are pushed back and not popped again
+	for ; len(conns) < int(c.OpenedConns()); {
 
 		conn, err := c.popConn(true)
 		if err != nil {
 			return err
 		}
+		conns = append(conns, conn)
 
 		if conn.openedAt.After(t) {
 			continue
@@ -172,9 +238,6 @@ func (c *ConnPool) DoEachConn(action func (conn *Conn) error) error {
 		if err != nil {
 			return err
 		}
-
-		ited++
-		conns = append(conns, conn)
 	}
 
 
@@ -153,7 +153,7 @@ func (c *ConnPool) Get(ctx context.Context, key string) (response.ResponseEntry,
 	}
 	defer c.pushConn(conn)
 
-	return c.Get(ctx, key)
+	return conn.Get(ctx, key)
 }
 
 // GetString() is a strict version of [Get] that only success if the value is stored as String in Skytable.
@@ -27,13 +27,35 @@ func (err ErrLocal) Unwrap() error {
 type ErrComu struct {
 	innerErr error
 	msg      string
+	unsent   bool
 }
 
 func NewComuError(msg string, err error) ErrComu {
 	return ErrComu{
-		err,
-		msg,
+		innerErr: err,
+		msg:      msg,
+	}
+}
+
+func newUnsentComuError(msg string, err error) ErrComu {
+	return ErrComu{
+		innerErr: err,
+		msg:      msg,
+		unsent:   true,
+	}
+}
+
+// Unsent returns true if the error occurred before any byte of the packet was written to the connection,
+// which means the packet was never executed by the server.
+func (err ErrComu) Unsent() bool {
+	return err.unsent
+}
+
+func writeError(written int, err error) ErrComu {
+	if written == 0 {
+		return newUnsentComuError("failed to write to conn", err)
 	}
+	return NewComuError("failed to write to conn", err)
 }
 
 func (err ErrComu) Error() string {
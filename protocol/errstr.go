@@ -18,4 +18,7 @@ func (r ErrorStringResponse) Error() string {
 
 const (
 	ErrStr_ContainerNotFound string = "container-not-found"
+	ErrStr_AlreadyExists     string = "err-already-exists"
+	ErrStr_BadListIndex      string = "bad-list-index"
+	ErrStr_ListIsEmpty       string = "list-is-empty"
 )
\ No newline at end of file
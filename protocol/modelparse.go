@@ -0,0 +1,280 @@
+package protocol
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// DDLType is a data type of a model description: a scalar like str, or a type parameterized by other types like list<binstr>.
+// Types this package doesn't know are kept by name.
+type DDLType struct {
+	Name   string    // Like "str", "binstr" or "list"
+	Params []DDLType // The element type of lists
+}
+
+// DDLTypeOf returns the DDLType of dt.
+func DDLTypeOf(dt DDLDataTypes) DDLType {
+	return DDLType{Name: dt.String()}
+}
+
+// ListOf returns the type of the lists of elem, like list<binstr>.
+func ListOf(elem DDLType) DDLType {
+	return DDLType{Name: DDLDataTypes_List.String(), Params: []DDLType{elem}}
+}
+
+func (t DDLType) String() string {
+	if len(t.Params) == 0 {
+		return t.Name
+	}
+
+	params := make([]string, len(t.Params))
+	for i, p := range t.Params {
+		params[i] = p.String()
+	}
+	return fmt.Sprintf("%s<%s>", t.Name, strings.Join(params, ","))
+}
+
+// Scalar returns the DDLDataTypes of types without parameters: str, binstr and the plain list.
+func (t DDLType) Scalar() (DDLDataTypes, bool) {
+	if len(t.Params) > 0 {
+		return 0, false
+	}
+
+	for _, dt := range []DDLDataTypes{DDLDataTypes_String, DDLDataTypes_BinaryString, DDLDataTypes_List} {
+		if t.Name == dt.String() {
+			return dt, true
+		}
+	}
+	return 0, false
+}
+
+// ParseDDLType parses a type like "str" or "list<list<binstr>>".
+func ParseDDLType(s string) (DDLType, error) {
+	p, err := newModelParser(s)
+	if err != nil {
+		return DDLType{}, err
+	}
+
+	t, err := p.parseType()
+	if err != nil {
+		return DDLType{}, err
+	}
+	if _, err = p.expect(tokenEOF); err != nil {
+		return DDLType{}, err
+	}
+	return t, nil
+}
+
+const (
+	tokenEOF   = 0
+	tokenIdent = 'a'
+	// Other tokens are the punctuation characters themselves
+)
+
+type modelToken struct {
+	kind byte
+	text string
+	pos  int
+}
+
+func (t modelToken) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of input"
+	case tokenIdent:
+		return fmt.Sprintf("%q", t.text)
+	default:
+		return fmt.Sprintf("'%c'", t.kind)
+	}
+}
+
+// tokenizeModel splits model descriptions like "Keymap { data:(str,list<binstr>), volatile:false }"
+// and models like "keymap(str,list<binstr>) volatile" into identifiers and punctuations.
+func tokenizeModel(s string) ([]modelToken, error) {
+	var tokens []modelToken
+
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case strings.ContainsRune("{}()<>,:", c):
+			tokens = append(tokens, modelToken{kind: byte(c), pos: i})
+			i++
+		case isModelIdentChar(c):
+			start := i
+			for i < len(s) && isModelIdentChar(rune(s[i])) {
+				i++
+			}
+			tokens = append(tokens, modelToken{kind: tokenIdent, text: s[start:i], pos: start})
+		default:
+			return nil, fmt.Errorf("%w: unexpected '%c' at %d", ErrInvalidModelDescriptionString, c, i)
+		}
+	}
+
+	return append(tokens, modelToken{kind: tokenEOF, pos: len(s)}), nil
+}
+
+func isModelIdentChar(c rune) bool {
+	return c == '_' || c == '-' || c < unicode.MaxASCII && (unicode.IsLetter(c) || unicode.IsDigit(c))
+}
+
+type modelParser struct {
+	tokens []modelToken
+	i      int
+}
+
+func newModelParser(s string) (*modelParser, error) {
+	tokens, err := tokenizeModel(s)
+	if err != nil {
+		return nil, err
+	}
+	return &modelParser{tokens: tokens}, nil
+}
+
+func (p *modelParser) peek() modelToken {
+	return p.tokens[p.i]
+}
+
+func (p *modelParser) next() modelToken {
+	t := p.tokens[p.i]
+	if t.kind != tokenEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *modelParser) expect(kind byte) (modelToken, error) {
+	t := p.next()
+	if t.kind != kind {
+		if kind == tokenIdent {
+			return t, fmt.Errorf("%w: expecting an identifier but got %s at %d", ErrInvalidModelDescriptionString, t, t.pos)
+		}
+		return t, fmt.Errorf("%w: expecting %s but got %s at %d", ErrInvalidModelDescriptionString, modelToken{kind: kind}, t, t.pos)
+	}
+	return t, nil
+}
+
+// parseType parses name or name<type,...>.
+func (p *modelParser) parseType() (DDLType, error) {
+	name, err := p.expect(tokenIdent)
+	if err != nil {
+		return DDLType{}, err
+	}
+
+	t := DDLType{Name: strings.ToLower(name.text)}
+	if p.peek().kind == '<' {
+		p.next()
+		t.Params, err = p.parseTypes('>')
+		if err != nil {
+			return DDLType{}, err
+		}
+	}
+	return t, nil
+}
+
+// parseTypes parses type,... up to the closing token, which is consumed.
+func (p *modelParser) parseTypes(closing byte) ([]DDLType, error) {
+	var types []DDLType
+	for {
+		t, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+
+		switch tok := p.next(); tok.kind {
+		case ',':
+		case closing:
+			return types, nil
+		default:
+			return nil, fmt.Errorf("%w: expecting ',' or '%c' but got %s at %d", ErrInvalidModelDescriptionString, closing, tok, tok.pos)
+		}
+	}
+}
+
+// parseDescription parses Kind { data:(type,...), property:bool, ... } as reported by INSPECT TABLE.
+func (p *modelParser) parseDescription() (kind string, types []DDLType, properties []string, err error) {
+	k, err := p.expect(tokenIdent)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if _, err = p.expect('{'); err != nil {
+		return "", nil, nil, err
+	}
+
+	for p.peek().kind != '}' {
+		field, err := p.expect(tokenIdent)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		if _, err = p.expect(':'); err != nil {
+			return "", nil, nil, err
+		}
+
+		if strings.ToLower(field.text) == "data" {
+			if _, err = p.expect('('); err != nil {
+				return "", nil, nil, err
+			}
+			types, err = p.parseTypes(')')
+			if err != nil {
+				return "", nil, nil, err
+			}
+		} else {
+			v, err := p.expect(tokenIdent)
+			if err != nil {
+				return "", nil, nil, err
+			}
+			switch strings.ToLower(v.text) {
+			case "true":
+				properties = append(properties, strings.ToLower(field.text))
+			case "false":
+			default:
+				return "", nil, nil, fmt.Errorf("%w: unsupported value of %s: %s", ErrInvalidModelDescriptionString, field.text, v.text)
+			}
+		}
+
+		if p.peek().kind != ',' {
+			break
+		}
+		p.next()
+	}
+
+	if _, err = p.expect('}'); err != nil {
+		return "", nil, nil, err
+	}
+	if _, err = p.expect(tokenEOF); err != nil {
+		return "", nil, nil, err
+	}
+	if types == nil {
+		return "", nil, nil, fmt.Errorf("%w: no data types", ErrInvalidModelDescriptionString)
+	}
+
+	return k.text, types, properties, nil
+}
+
+// parseModel parses kind(type,...) property ... as accepted by CREATE TABLE.
+func (p *modelParser) parseModel() (kind string, types []DDLType, properties []string, err error) {
+	k, err := p.expect(tokenIdent)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if _, err = p.expect('('); err != nil {
+		return "", nil, nil, err
+	}
+	types, err = p.parseTypes(')')
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	for p.peek().kind != tokenEOF {
+		prop, err := p.expect(tokenIdent)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		properties = append(properties, strings.ToLower(prop.text))
+	}
+
+	return k.text, types, properties, nil
+}
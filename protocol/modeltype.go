@@ -3,19 +3,21 @@ package protocol
 import (
 	"errors"
 	"fmt"
-	"regexp"
+	"strings"
 )
 
 var ErrInvalidModelDescriptionString = errors.New("invalid model description string")
 
-var keymapRegex *regexp.Regexp = regexp.MustCompile(`Keymap\s?{\s?data:\s?\((.+),(.+)\),\s?volatile:\s?(true|false)\s?}`)
-
 type ModelType byte
 
 const (
 	ModelTypeKeyMap ModelType = iota
 )
 
+// ModelDescription is a table model, like KeyMapDescription.
+//
+// Compare descriptions with SameModel, not ==: TypedKeyMapDescription and GenericModelDescription hold slices,
+// so == panics on them, and a KeyMapDescription differs from the TypedKeyMapDescription of the same types.
 type ModelDescription interface {
 	Model() string
 	Properties() string
@@ -39,32 +41,137 @@ func (d KeyMapDescription) Properties() string {
 	}
 }
 
+// String returns the description as reported by INSPECT TABLE.
+func (d KeyMapDescription) String() string {
+	return fmt.Sprintf("Keymap { data:(%s,%s), volatile:%t }", d.KeyType, d.ValueType, d.Volatile)
+}
+
+// TypedKeyMapDescription describes keymaps whose types are not all DDLDataTypes, like keymap(str,list<binstr>).
+// It's not comparable, see SameModel.
+type TypedKeyMapDescription struct {
+	KeyType   DDLType
+	ValueType DDLType
+	Volatile  bool
+}
+
+func (d TypedKeyMapDescription) Model() string {
+	return fmt.Sprintf("keymap(%s,%s)", d.KeyType, d.ValueType)
+}
+
+func (d TypedKeyMapDescription) Properties() string {
+	if d.Volatile {
+		return "volatile"
+	} else {
+		return ""
+	}
+}
+
+// String returns the description as reported by INSPECT TABLE.
+func (d TypedKeyMapDescription) String() string {
+	return fmt.Sprintf("Keymap { data:(%s,%s), volatile:%t }", d.KeyType, d.ValueType, d.Volatile)
+}
+
+// GenericModelDescription describes the models this package doesn't know, like future model kinds.
+// It's not comparable, see SameModel.
+type GenericModelDescription struct {
+	Kind  string    // As reported by INSPECT TABLE, like "Keymap"
+	Types []DDLType // The types of the data
+	Flags []string  // The properties which are true, like "volatile"
+}
+
+func (d GenericModelDescription) Model() string {
+	types := make([]string, len(d.Types))
+	for i, t := range d.Types {
+		types[i] = t.String()
+	}
+	return fmt.Sprintf("%s(%s)", strings.ToLower(d.Kind), strings.Join(types, ","))
+}
+
+func (d GenericModelDescription) Properties() string {
+	return strings.Join(d.Flags, " ")
+}
+
+// String returns the description as reported by INSPECT TABLE.
+func (d GenericModelDescription) String() string {
+	types := make([]string, len(d.Types))
+	for i, t := range d.Types {
+		types[i] = t.String()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s { data:(%s)", d.Kind, strings.Join(types, ","))
+	for _, p := range d.Flags {
+		fmt.Fprintf(&b, ", %s:true", p)
+	}
+	b.WriteString(" }")
+	return b.String()
+}
+
+// ParseDescription parses the model description reported by INSPECT TABLE, like "Keymap { data:(str,list<binstr>), volatile:false }".
+//
+// Keymaps are returned as KeyMapDescription if their types are all DDLDataTypes, otherwise as TypedKeyMapDescription.
+// Other models are returned as GenericModelDescription.
 func ParseDescription (descStr string) (ModelDescription, error) {
-	if matches := keymapRegex.FindStringSubmatch(descStr); matches != nil {
-		desc := KeyMapDescription{}
-		switch matches[1] {
-		case DDLDataTypes_String.String():
-			desc.KeyType = DDLDataTypes_String
-		case DDLDataTypes_BinaryString.String():
-			desc.KeyType = DDLDataTypes_BinaryString
-		default:
-			return nil, ErrInvalidModelDescriptionString
+	p, err := newModelParser(descStr)
+	if err != nil {
+		return nil, err
+	}
+
+	kind, types, properties, err := p.parseDescription()
+	if err != nil {
+		return nil, err
+	}
+
+	return newModelDescription(kind, types, properties), nil
+}
+
+// ParseModel parses a model as accepted by CREATE TABLE, like "keymap(str,list<binstr>) volatile",
+// so ParseModel(d.Model() + " " + d.Properties()) returns a description equal to d.
+func ParseModel (model string) (ModelDescription, error) {
+	p, err := newModelParser(model)
+	if err != nil {
+		return nil, err
+	}
+
+	kind, types, properties, err := p.parseModel()
+	if err != nil {
+		return nil, err
+	}
+
+	return newModelDescription(kind, types, properties), nil
+}
+
+func newModelDescription (kind string, types []DDLType, properties []string) ModelDescription {
+	if strings.ToLower(kind) == "keymap" && len(types) == 2 {
+		volatile := false
+		known := true
+		for _, p := range properties {
+			if p == "volatile" {
+				volatile = true
+			} else {
+				known = false
+			}
 		}
-		switch matches[2] {
-		case DDLDataTypes_String.String():
-			desc.ValueType = DDLDataTypes_String
-		case DDLDataTypes_BinaryString.String():
-			desc.ValueType = DDLDataTypes_BinaryString
-		case DDLDataTypes_List.String():
-			desc.ValueType = DDLDataTypes_List
+
+		keyType, keyScalar := types[0].Scalar()
+		valueType, valueScalar := types[1].Scalar()
+		switch {
+		case !known:
+		case keyScalar && valueScalar:
+			return KeyMapDescription{keyType, valueType, volatile}
 		default:
-			return nil, ErrInvalidModelDescriptionString
-		}
-		if matches[3] == "true" {
-			desc.Volatile = true
+			return TypedKeyMapDescription{types[0], types[1], volatile}
 		}
-		return desc, nil
 	}
 
-	return nil, ErrInvalidModelDescriptionString
-}
\ No newline at end of file
+	return GenericModelDescription{kind, types, properties}
+}
+
+// SameModel reports whether the descriptions are of the same model and properties,
+// like a KeyMapDescription and the TypedKeyMapDescription of the same types.
+func SameModel (a, b ModelDescription) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return strings.EqualFold(a.Model(), b.Model()) && strings.Join(strings.Fields(a.Properties()), " ") == strings.Join(strings.Fields(b.Properties()), " ")
+}
@@ -1,6 +1,8 @@
 package protocol
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
 	"testing"
 )
@@ -21,6 +23,15 @@ func TestParseDescription(t *testing.T) {
 		  KeyMapDescription { DDLDataTypes_BinaryString, DDLDataTypes_String, false }, false },
 		{ "str,bin", args { "Keymap { data: (str,binstr), volatile: true }" },
 		  KeyMapDescription { DDLDataTypes_String, DDLDataTypes_BinaryString, true }, false },
+		{ "str,list<binstr>", args { "Keymap { data:(str,list<binstr>), volatile:false }" },
+		  TypedKeyMapDescription { DDLTypeOf(DDLDataTypes_String), ListOf(DDLTypeOf(DDLDataTypes_BinaryString)), false }, false },
+		{ "nested", args { "Keymap { data:(binstr,list<list<str>>), volatile:true }" },
+		  TypedKeyMapDescription { DDLTypeOf(DDLDataTypes_BinaryString), ListOf(ListOf(DDLTypeOf(DDLDataTypes_String))), true }, false },
+		{ "unknown model", args { "Sequence { data:(uint64), volatile:false, compressed:true }" },
+		  GenericModelDescription { "Sequence", []DDLType{ { Name: "uint64" } }, []string{ "compressed" } }, false },
+		{ "unclosed type", args { "Keymap { data:(str,list<binstr), volatile:false }" }, nil, true },
+		{ "no data", args { "Keymap { volatile:false }" }, nil, true },
+		{ "trailing", args { "Keymap { data:(str,str), volatile:false } x" }, nil, true },
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -35,3 +46,47 @@ func TestParseDescription(t *testing.T) {
 		})
 	}
 }
+
+
+func TestParseModel(t *testing.T) {
+	for _, d := range []ModelDescription{
+		KeyMapDescription{DDLDataTypes_String, DDLDataTypes_BinaryString, false},
+		KeyMapDescription{DDLDataTypes_BinaryString, DDLDataTypes_List, true},
+		TypedKeyMapDescription{DDLTypeOf(DDLDataTypes_String), ListOf(DDLTypeOf(DDLDataTypes_String)), true},
+		TypedKeyMapDescription{DDLTypeOf(DDLDataTypes_String), ListOf(ListOf(DDLTypeOf(DDLDataTypes_BinaryString))), false},
+		GenericModelDescription{"sequence", []DDLType{{Name: "uint64"}}, []string{"volatile", "compressed"}},
+	} {
+		got, err := ParseModel(d.Model() + " " + d.Properties())
+		if err != nil {
+			t.Fatalf("ParseModel(%q) error = %v", d.Model(), err)
+		}
+		if !reflect.DeepEqual(got, d) {
+			t.Errorf("ParseModel() = %#v, want %#v", got, d)
+		}
+
+		got, err = ParseDescription(fmt.Sprint(d))
+		if err != nil {
+			t.Fatalf("ParseDescription(%q) error = %v", d, err)
+		}
+		if !reflect.DeepEqual(got, d) {
+			t.Errorf("ParseDescription() = %#v, want %#v", got, d)
+		}
+	}
+
+	if _, err := ParseModel("keymap(str,)"); !errors.Is(err, ErrInvalidModelDescriptionString) {
+		t.Errorf("ParseModel() error = %v, want %v", err, ErrInvalidModelDescriptionString)
+	}
+}
+
+func TestSameModel(t *testing.T) {
+	simple := KeyMapDescription{DDLDataTypes_String, DDLDataTypes_String, true}
+	typed := TypedKeyMapDescription{DDLTypeOf(DDLDataTypes_String), DDLTypeOf(DDLDataTypes_String), true}
+	if !SameModel(simple, typed) {
+		t.Errorf("SameModel(%v, %v) = false", simple, typed)
+	}
+
+	typed.Volatile = false
+	if SameModel(simple, typed) {
+		t.Errorf("SameModel(%v, %v) = true", simple, typed)
+	}
+}
@@ -0,0 +1,465 @@
+package skytable
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/No3371/go-skytable/action"
+	"github.com/No3371/go-skytable/protocol"
+	"github.com/No3371/go-skytable/response"
+)
+
+// Checkpoint stores the progress of a [Rebalancer], the last key it processed on each source shard.
+type Checkpoint interface {
+	// Load returns "" if the shard has no progress.
+	Load(shard string) (lastKey string, err error)
+	Save(shard string, lastKey string) error
+}
+
+// MemoryCheckpoint keeps the progress in memory, to resume a [Rebalancer] in the same process.
+type MemoryCheckpoint struct {
+	mu   sync.Mutex
+	last map[string]string
+}
+
+func (cp *MemoryCheckpoint) Load(shard string) (string, error) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	return cp.last[shard], nil
+}
+
+func (cp *MemoryCheckpoint) Save(shard string, lastKey string) error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	if cp.last == nil {
+		cp.last = make(map[string]string)
+	}
+	cp.last[shard] = lastKey
+	return nil
+}
+
+// FileCheckpoint keeps the progress in a JSON file, to resume a [Rebalancer] after a restart.
+type FileCheckpoint struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+func (cp *FileCheckpoint) read() (map[string]string, error) {
+	last := make(map[string]string)
+
+	data, err := os.ReadFile(cp.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return last, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &last); err != nil {
+		return nil, fmt.Errorf("FileCheckpoint: %s: %w", cp.Path, err)
+	}
+	return last, nil
+}
+
+func (cp *FileCheckpoint) Load(shard string) (string, error) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	last, err := cp.read()
+	if err != nil {
+		return "", err
+	}
+	return last[shard], nil
+}
+
+func (cp *FileCheckpoint) Save(shard string, lastKey string) error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	last, err := cp.read()
+	if err != nil {
+		return err
+	}
+	last[shard] = lastKey
+
+	data, err := json.Marshal(last)
+	if err != nil {
+		return err
+	}
+
+	// Written aside then renamed, so a crash never leaves a partial file
+	tmp := cp.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, cp.Path)
+}
+
+type RebalanceOptions struct {
+	Entity        string     // The table to rebalance, "" for the current table of the conns
+	Lists         bool       // If true, the table is a list table and keys are copied with LGET/LSET, otherwise with MGET/MSET
+	BatchSize     int        // Keys per batch, 100 if 0
+	KeysPerSecond float64    // Limits the rate keys are scanned at, unlimited if 0
+	DeleteSource  bool       // If true, moved keys are deleted from their source shard
+	Checkpoint    Checkpoint // Saved after every batch and loaded on Run, progress is not kept if nil
+}
+
+type RebalanceStats struct {
+	Scanned uint64 // Keys listed on the source shards
+	Moved   uint64 // Keys copied to their new shard
+	Skipped uint64 // Keys already on their new shard, or deleted meanwhile
+}
+
+// Rebalancer moves the keys of a table from the ring of a [ShardedClient] to the ring of another,
+// usually the same shards plus a new one.
+//
+// Each source shard is listed with LSKEYS, sorted, and the keys owned by another shard on the new ring
+// are copied in batches. Copies never overwrite (MSET, LSET): keys already written on the new ring,
+// like by a [DualClient] during the cutover, are kept. The last processed key of each shard is saved to the Checkpoint,
+// so an interrupted Run resumes after it.
+type Rebalancer struct {
+	from, to *ShardedClient
+	opts     RebalanceOptions
+}
+
+// NewRebalancer creates a Rebalancer moving keys from the ring of `from` to the ring of `to`.
+// Shards are matched by name.
+func NewRebalancer(from, to *ShardedClient, opts RebalanceOptions) *Rebalancer {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+
+	return &Rebalancer{
+		from: from,
+		to:   to,
+		opts: opts,
+	}
+}
+
+// Run moves the keys, one source shard after another.
+func (r *Rebalancer) Run(ctx context.Context) (stats RebalanceStats, err error) {
+	for _, s := range r.from.shards {
+		err = r.runShard(ctx, s, &stats)
+		if err != nil {
+			return stats, fmt.Errorf("*Rebalancer.Run(): %s: %w", s.Name, err)
+		}
+	}
+
+	return stats, nil
+}
+
+func (r *Rebalancer) runShard(ctx context.Context, s Shard, stats *RebalanceStats) error {
+	last := ""
+	if r.opts.Checkpoint != nil {
+		var err error
+		last, err = r.opts.Checkpoint.Load(s.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	keys, err := listKeys(ctx, s.Pool, r.opts.Entity)
+	if err != nil {
+		return err
+	}
+	keys = keys[sort.SearchStrings(keys, last):]
+	if len(keys) > 0 && keys[0] == last {
+		keys = keys[1:]
+	}
+
+	for len(keys) > 0 {
+		n := r.opts.BatchSize
+		if n > len(keys) {
+			n = len(keys)
+		}
+		batch := keys[:n]
+		keys = keys[n:]
+
+		start := time.Now()
+		stats.Scanned += uint64(len(batch))
+
+		var moving []string
+		for _, k := range batch {
+			if r.to.shards[r.to.shardOf(k)].Name != s.Name {
+				moving = append(moving, k)
+			}
+		}
+
+		moved, err := r.move(ctx, s.Pool, moving)
+		if err != nil {
+			return err
+		}
+		stats.Moved += moved
+		stats.Skipped += uint64(len(batch)) - moved
+
+		if r.opts.Checkpoint != nil {
+			err = r.opts.Checkpoint.Save(s.Name, batch[len(batch)-1])
+			if err != nil {
+				return err
+			}
+		}
+
+		err = r.throttle(ctx, start, len(batch))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// move copies the keys from the source shard to their shard on the new ring, returning how many were copied.
+func (r *Rebalancer) move(ctx context.Context, src *ConnPool, keys []string) (moved uint64, err error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	var copied []string
+	if r.opts.Lists {
+		for _, k := range keys {
+			list, err := src.LGet(ctx, k)
+			if errors.Is(err, protocol.ErrCodeNil) {
+				continue
+			}
+			if err != nil {
+				return moved, err
+			}
+
+			err = r.to.LSet(ctx, k, list.Elements)
+			if errors.Is(err, protocol.ErrCodeOverwriteError) {
+				continue
+			}
+			if err != nil {
+				return moved, err
+			}
+			moved++
+			copied = append(copied, k)
+		}
+	} else {
+		values, err := src.MGet(ctx, keys)
+		if err != nil {
+			return 0, err
+		}
+
+		var entries []action.KVPair
+		for i, v := range values.Elements {
+			if v != nil {
+				entries = append(entries, action.KVPair{K: keys[i], V: v})
+			}
+		}
+		if len(entries) == 0 {
+			return 0, nil
+		}
+
+		moved, err = r.to.MSet(ctx, entries)
+		if err != nil {
+			return moved, err
+		}
+		for _, e := range entries {
+			copied = append(copied, e.K)
+		}
+	}
+
+	if r.opts.DeleteSource && len(copied) > 0 {
+		_, err = src.Del(ctx, copied)
+		if err != nil {
+			return moved, err
+		}
+	}
+
+	return moved, nil
+}
+
+func (r *Rebalancer) throttle(ctx context.Context, start time.Time, n int) error {
+	if r.opts.KeysPerSecond <= 0 {
+		return nil
+	}
+
+	wait := time.Until(start.Add(time.Duration(float64(n) / r.opts.KeysPerSecond * float64(time.Second))))
+	if wait <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(wait)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// listKeys returns all the keys of the entity on the pool, sorted.
+func listKeys(ctx context.Context, p *ConnPool, entity string) ([]string, error) {
+	size, err := p.DBSize(ctx, entity)
+	if err != nil {
+		return nil, err
+	}
+	if size == 0 {
+		return nil, nil
+	}
+
+	arr, err := p.LSKeys(ctx, entity, size)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(arr.Elements))
+	for _, e := range arr.Elements {
+		switch k := e.(type) {
+		case string:
+			keys = append(keys, k)
+		case []byte:
+			keys = append(keys, string(k))
+		default:
+			return nil, protocol.NewUnexpectedProtocolError(fmt.Sprintf("LSKeys(): Unexpected key: %v", e), nil)
+		}
+	}
+
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// DualClient is a [ShardedClient] on the new ring which falls back to the old ring during a rebalance,
+// so the data stays available while the [Rebalancer] runs.
+//
+// GET, MGET and LGET read the new ring, then the old one for missing keys.
+// SET fails if the key exists on either ring, UPDATE updates the key on the new ring even if it's not moved yet,
+// and DEL deletes the keys on both rings. It's best-effort: the Rebalancer reads a key on the old ring before writing it
+// on the new one, so a DEL landing between the two deletes it on both rings and the copy then brings it back.
+// The other methods only use the new ring, list modifications included: move the lists before modifying them.
+type DualClient struct {
+	*ShardedClient
+	old *ShardedClient
+}
+
+func NewDualClient(from, to *ShardedClient) *DualClient {
+	return &DualClient{
+		ShardedClient: to,
+		old:           from,
+	}
+}
+
+func dualRead[T any](c *DualClient, f func(c *ShardedClient) (T, error)) (T, error) {
+	v, err := f(c.ShardedClient)
+	if errors.Is(err, protocol.ErrCodeNil) {
+		return f(c.old)
+	}
+	return v, err
+}
+
+// https://docs.skytable.io/actions/get
+func (c *DualClient) Get(ctx context.Context, key string) (response.ResponseEntry, error) {
+	return dualRead(c, func(s *ShardedClient) (response.ResponseEntry, error) {
+		return s.Get(ctx, key)
+	})
+}
+
+// a strict version of [Get] that only success if the value is stored as String in Skytable.
+func (c *DualClient) GetString(ctx context.Context, key string) (string, error) {
+	return dualRead(c, func(s *ShardedClient) (string, error) {
+		return s.GetString(ctx, key)
+	})
+}
+
+// a strict version of [Get] that only success if the value is stored as BinaryString in Skytable.
+func (c *DualClient) GetBytes(ctx context.Context, key string) ([]byte, error) {
+	return dualRead(c, func(s *ShardedClient) ([]byte, error) {
+		return s.GetBytes(ctx, key)
+	})
+}
+
+// https://docs.skytable.io/actions/mget
+func (c *DualClient) MGet(ctx context.Context, keys []string) (*protocol.TypedArray, error) {
+	arr, err := c.ShardedClient.MGet(ctx, keys)
+	if err != nil {
+		return arr, err
+	}
+
+	var missing []int
+	for i, e := range arr.Elements {
+		if e == nil {
+			missing = append(missing, i)
+		}
+	}
+	if len(missing) == 0 {
+		return arr, nil
+	}
+
+	old, err := c.old.MGet(ctx, pick(keys, missing))
+	if err != nil {
+		return arr, err
+	}
+	for i, j := range missing {
+		arr.Elements[j] = old.Elements[i]
+	}
+	return arr, nil
+}
+
+// https://docs.skytable.io/actions/lget#lget
+func (c *DualClient) LGet(ctx context.Context, listName string) (*protocol.TypedArray, error) {
+	return dualRead(c, func(s *ShardedClient) (*protocol.TypedArray, error) {
+		return s.LGet(ctx, listName)
+	})
+}
+
+// https://docs.skytable.io/actions/set
+func (c *DualClient) Set(ctx context.Context, key string, value any) error {
+	if c.old.ShardFor(key) != c.ShardFor(key) {
+		existing, err := c.old.Exists(ctx, []string{key})
+		if err != nil {
+			return err
+		}
+		if existing > 0 {
+			return protocol.ErrCodeOverwriteError
+		}
+	}
+
+	return c.ShardedClient.Set(ctx, key, value)
+}
+
+// https://docs.skytable.io/actions/update
+func (c *DualClient) Update(ctx context.Context, key string, value any) error {
+	err := c.ShardedClient.Update(ctx, key, value)
+	if !errors.Is(err, protocol.ErrCodeNil) {
+		return err
+	}
+
+	existing, err := c.old.Exists(ctx, []string{key})
+	if err != nil {
+		return err
+	}
+	if existing == 0 {
+		return protocol.ErrCodeNil
+	}
+
+	_, err = c.ShardedClient.USet(ctx, action.KVPair{K: key, V: value})
+	return err
+}
+
+// https://docs.skytable.io/actions/del
+//
+// Returns the keys deleted on the new ring, or on the old ring if more.
+func (c *DualClient) Del(ctx context.Context, keys []string) (uint64, error) {
+	deleted, err := c.ShardedClient.Del(ctx, keys)
+	if err != nil {
+		return deleted, err
+	}
+
+	oldDeleted, err := c.old.Del(ctx, keys)
+	if oldDeleted > deleted {
+		deleted = oldDeleted
+	}
+	return deleted, err
+}
@@ -0,0 +1,143 @@
+package skytable_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/protocol"
+)
+
+func TestRebalancer(t *testing.T) {
+	ctx := context.Background()
+	shards := newTestShards(t, 3)
+	from := newTestShardedClient(t, shards[:2], skytable.ShardedOptions{})
+	to := newTestShardedClient(t, shards, skytable.ShardedOptions{})
+
+	keys := testKeys(200)
+	for _, k := range keys {
+		if err := from.Set(ctx, k, "v-"+k); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cp := &skytable.FileCheckpoint{Path: filepath.Join(t.TempDir(), "checkpoint.json")}
+	stats, err := skytable.NewRebalancer(from, to, skytable.RebalanceOptions{
+		BatchSize:    16,
+		DeleteSource: true,
+		Checkpoint:   cp,
+	}).Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Scanned != 200 || stats.Moved == 0 || stats.Moved+stats.Skipped != 200 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	for _, k := range keys {
+		v, err := to.ShardFor(k).GetBytes(ctx, k)
+		if err != nil || string(v) != "v-"+k {
+			t.Fatalf("expecting %s on its new shard but got %s (%v)", k, v, err)
+		}
+	}
+	size, err := to.DBSize(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != 200 {
+		t.Fatalf("expecting the moved keys to be deleted from their source but got %d keys", size)
+	}
+
+	// Resumed from the checkpoint, nothing left to scan
+	stats, err = skytable.NewRebalancer(from, to, skytable.RebalanceOptions{Checkpoint: cp}).Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Scanned != 0 {
+		t.Fatalf("expecting the run to resume after the checkpoint but %d keys were scanned", stats.Scanned)
+	}
+}
+
+func TestRebalancer_Checkpoint(t *testing.T) {
+	ctx := context.Background()
+	shards := newTestShards(t, 2)
+	from := newTestShardedClient(t, shards[:1], skytable.ShardedOptions{})
+	to := newTestShardedClient(t, shards, skytable.ShardedOptions{})
+
+	keys := testKeys(20)
+	for _, k := range keys {
+		if err := from.Set(ctx, k, k); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sort.Strings(keys)
+
+	cp := &skytable.MemoryCheckpoint{}
+	cp.Save(shards[0].Name, keys[9])
+
+	stats, err := skytable.NewRebalancer(from, to, skytable.RebalanceOptions{Checkpoint: cp}).Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Scanned != 10 {
+		t.Fatalf("expecting the 10 keys after the checkpoint to be scanned but got %d", stats.Scanned)
+	}
+	if last, _ := cp.Load(shards[0].Name); last != keys[19] {
+		t.Fatalf("expecting the checkpoint to be %s but got %s", keys[19], last)
+	}
+}
+
+func TestDualClient(t *testing.T) {
+	ctx := context.Background()
+	shards := newTestShards(t, 2)
+	from := newTestShardedClient(t, shards[:1], skytable.ShardedOptions{})
+	to := newTestShardedClient(t, shards, skytable.ShardedOptions{})
+	var st skytable.Skytable
+	dual := skytable.NewDualClient(from, to)
+	st = dual
+
+	// A key moving to the new shard
+	var k string
+	for _, key := range testKeys(100) {
+		if to.ShardFor(key) == shards[1].Pool {
+			k = key
+			break
+		}
+	}
+	if err := from.Set(ctx, k, "old"); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, err := st.GetBytes(ctx, k); err != nil || string(v) != "old" {
+		t.Fatalf("expecting the read to fall back to the old ring but got %s (%v)", v, err)
+	}
+	if err := st.Set(ctx, k, "new"); !errors.Is(err, protocol.ErrCodeOverwriteError) {
+		t.Fatalf("expecting ErrCodeOverwriteError but got %v", err)
+	}
+	if err := st.Update(ctx, k, "updated"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The update is not overwritten by the rebalance
+	if _, err := skytable.NewRebalancer(from, to, skytable.RebalanceOptions{}).Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	arr, err := st.MGet(ctx, []string{k})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := arr.Elements[0].([]byte); string(v) != "updated" {
+		t.Fatalf("expecting the updated value but got %v", arr.Elements[0])
+	}
+
+	// Deleted on both rings
+	if _, err := st.Del(ctx, []string{k}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.Get(ctx, k); !errors.Is(err, protocol.ErrCodeNil) {
+		t.Fatalf("expecting ErrCodeNil but got %v", err)
+	}
+}
@@ -7,16 +7,18 @@ import (
 )
 
 func (rr ResponseReader) readFlatArray(items int64) (*protocol.Array, error) {
+	if items < 0 {
+		return nil, ErrInvalidPacket
+	}
+
 	arr := protocol.Array{
 		ArrayType: protocol.CompoundTypeFlatArray,
-		Elements:  make([]interface{}, items),
+		Elements:  make([]interface{}, 0, preallocSize(items)),
 	}
 
-	var err error
-
 	for i := int64(0); i < items; i++ {
-		var dt protocol.DataType
-		dt, arr.Elements[i], err = rr.readOneEntry()
+		dt, e, err := rr.readOneEntry()
+		arr.Elements = append(arr.Elements, e)
 		if err != nil {
 			return &arr, fmt.Errorf("failed to read flat array entry #%d/%d: %w", i + 1, items, err)
 		}
@@ -2,6 +2,7 @@ package response
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -16,6 +17,10 @@ var ErrInvalidPacket = errors.New("invalid packet")
 var ErrElementSizeMismatch = errors.New("element size mismatch")
 var ErrNotImplementedDataType = errors.New("datatype not implemented")
 
+// maxPrealloc bounds the memory allocated ahead for sizes declared in packets,
+// larger elements grow as the bytes actually arrive.
+const maxPrealloc = 1 << 16
+
 type ResponseEntry struct {
 	DataType protocol.DataType
 	Value    any
@@ -60,7 +65,11 @@ func (rr ResponseReader) Read(r io.Reader) ([]ResponseEntry, error) {
 		return nil, fmt.Errorf("an error occured when reading metaframe: %w", err)
 	}
 
-	var entries []ResponseEntry = make([]ResponseEntry, count)
+	if count < 0 {
+		return nil, ErrInvalidPacket
+	}
+
+	var entries []ResponseEntry = make([]ResponseEntry, 0, preallocSize(count))
 
 	for i := int64(0); i < count; i++ {
 		dt, v, err := rr.readOneEntry()
@@ -75,11 +84,11 @@ func (rr ResponseReader) Read(r io.Reader) ([]ResponseEntry, error) {
 			return nil, protocol.ErrCodePacketError
 		}
 
-		entries[i] = ResponseEntry{
+		entries = append(entries, ResponseEntry{
 			Value:    v,
 			DataType: dt,
 			Err:      err,
-		}
+		})
 	}
 
 	return entries, nil
@@ -152,6 +161,9 @@ func (rr ResponseReader) readOneEntry() (dt protocol.DataType, v interface{}, er
 	case protocol.DataTypeTypedArray: // typed array
 		fallthrough
 	case protocol.DataTypeTypedNonNullArray: // typed non-null array
+		if len(read) < 2 {
+			return 0, nil, ErrInvalidPacket
+		}
 		size, err = strconv.ParseInt(string(read[1:len(read)-1]), 10, 64)
 		if err != nil {
 			return 0, nil, err
@@ -221,7 +233,7 @@ func (rr ResponseReader) readOneEntryTyped(dt protocol.DataType) (v interface{},
 		log.Printf("    typed read: %v", read)
 	}
 
-	if read[0] == 0 && read[1] == '\n' { // NULL
+	if len(read) == 2 && read[0] == 0 { // NULL
 		return nil, nil
 	}
 
@@ -279,6 +291,41 @@ func (rr ResponseReader) readOneEntryTyped(dt protocol.DataType) (v interface{},
 	}
 }
 
+// preallocSize returns the capacity to allocate ahead for `size` declared in a packet.
+func preallocSize(size int64) int64 {
+	if size > maxPrealloc {
+		return maxPrealloc
+	}
+	return size
+}
+
+// readFull reads exactly `size` bytes, without trusting `size` for allocation.
+func (rr ResponseReader) readFull(size int64) ([]byte, error) {
+	if size < 0 {
+		return nil, ErrInvalidPacket
+	}
+
+	if size <= maxPrealloc {
+		buf := make([]byte, size)
+		_, err := io.ReadFull(rr.reader, buf)
+		if err != nil {
+			return nil, err
+		}
+		return buf, nil
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, maxPrealloc))
+	_, err := io.CopyN(buf, rr.reader, size)
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
 func (rr ResponseReader) ReadSimpleType(t protocol.SimpleType, size int64) (interface{}, error) {
 	switch t {
 	case protocol.SimpleTypeString: // string
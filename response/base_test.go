@@ -0,0 +1,102 @@
+package response_test
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/No3371/go-skytable/action"
+	"github.com/No3371/go-skytable/protocol"
+	"github.com/No3371/go-skytable/response"
+)
+
+func FuzzResponseReader_Read(f *testing.F) {
+	seeds := []string{
+		"*1\n!1\n0\n",
+		"*1\n+5\nhello\n",
+		"*1\n?3\n\x00\x01\x02\n",
+		"*1\n:2\n42\n",
+		"*1\n;2\n-1\n",
+		"*1\n%4\n1.25\n",
+		"*1\n@+2\n1\na\n\x00\n",
+		"*1\n^?1\n2\nab\n",
+		"*1\n_2\n+1\na\n:1\n1\n",
+		"*2\n!1\n0\n!1\n1\n",
+		"*1\n!13\nerr-something\n",
+		"*\n",
+		"\n",
+		"*1\n@\n",
+		"*-1\n",
+		"*1\n+-5\n",
+		"*1\n@+-1\n",
+		"*1\n?99999999999999\nab\n",
+		"*9999999999999\n+1\na\n",
+	}
+	for _, s := range seeds {
+		f.Add([]byte(s))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		rr := response.NewResponseReader()
+		rr.Read(bytes.NewReader(data)) // must not panic
+	})
+}
+
+func appendTypedPacket(t *testing.T, v any) string {
+	t.Helper()
+
+	b := &strings.Builder{}
+	b.WriteString("*1\n")
+	if err := action.AppendElement(b, true, v); err != nil {
+		t.Fatalf("AppendElement(%T) error: %v", v, err)
+	}
+	return b.String()
+}
+
+func readSingle(t *testing.T, packet string) any {
+	t.Helper()
+
+	entries, err := response.NewResponseReader().Read(strings.NewReader(packet))
+	if err != nil {
+		t.Fatalf("Read(%q) error: %v", packet, err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Read(%q) got %d entries, want 1", packet, len(entries))
+	}
+	if entries[0].Err != nil {
+		t.Fatalf("Read(%q) entry error: %v", packet, entries[0].Err)
+	}
+	return entries[0].Value
+}
+
+func FuzzEncodeDecode(f *testing.F) {
+	f.Add("", []byte{}, int64(0), uint64(0), float32(0))
+	f.Add("あ得\n", []byte{0, '\n'}, int64(-9223372036854775808), uint64(18446744073709551615), float32(-1.5))
+
+	f.Fuzz(func(t *testing.T, s string, bs []byte, i int64, u uint64, fl float32) {
+		values := []any{s, bs, i, u}
+		if !math.IsNaN(float64(fl)) {
+			values = append(values, fl)
+		}
+
+		for _, v := range values {
+			if got := readSingle(t, appendTypedPacket(t, v)); !reflect.DeepEqual(got, v) {
+				t.Fatalf("round trip of %T = %#v, want %#v", v, got, v)
+			}
+		}
+
+		arr := &protocol.TypedArray{
+			Array: protocol.Array{
+				ArrayType: protocol.CompoundTypeTypedArray,
+				Elements:  []any{s, s + s},
+			},
+			ElementType: protocol.SimpleTypeString,
+		}
+		got, ok := readSingle(t, appendTypedPacket(t, arr)).(*protocol.TypedArray)
+		if !ok || !reflect.DeepEqual(got.Elements, arr.Elements) {
+			t.Fatalf("round trip of typed array = %#v, want %#v", got, arr)
+		}
+	})
+}
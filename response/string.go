@@ -1,10 +1,7 @@
 package response
 
-import "io"
-
 func (rr ResponseReader) readStringValue(bytes int64) (string, error) {
-	var str []byte = make([]byte, bytes)
-	_, err := io.ReadFull(rr.reader, str)
+	str, err := rr.readFull(bytes)
 	if err != nil {
 		return "", err
 	}
@@ -7,18 +7,21 @@ import (
 )
 
 func (rr ResponseReader) readTypedArray(t protocol.SimpleType, items int64) (*protocol.TypedArray, error) {
+	if items < 0 {
+		return nil, ErrInvalidPacket
+	}
+
 	arr := protocol.TypedArray{
 		Array: protocol.Array{
 			ArrayType: protocol.CompoundTypeTypedArray,
-			Elements:  make([]interface{}, items),
+			Elements:  make([]interface{}, 0, preallocSize(items)),
 		},
 		ElementType: t,
 	}
 
-	var err error
-
 	for i := int64(0); i < items; i++ {
-		arr.Elements[i], err = rr.readOneEntryTyped(protocol.DataType(t))
+		e, err := rr.readOneEntryTyped(protocol.DataType(t))
+		arr.Elements = append(arr.Elements, e)
 		if err != nil {
 			return &arr, fmt.Errorf("failed to read typed array entry #%d/%d: %w", i+1, items, err)
 		}
@@ -28,18 +31,21 @@ func (rr ResponseReader) readTypedArray(t protocol.SimpleType, items int64) (*pr
 }
 
 func (rr ResponseReader) readTypedNonNullArray(t protocol.SimpleType, items int64) (*protocol.TypedArray, error) {
+	if items < 0 {
+		return nil, ErrInvalidPacket
+	}
+
 	arr := protocol.TypedArray{
 		Array: protocol.Array{
 			ArrayType: protocol.CompoundTypeTypedNonNullArray,
-			Elements:  make([]interface{}, items),
+			Elements:  make([]interface{}, 0, preallocSize(items)),
 		},
 		ElementType: t,
 	}
 
-	var err error
-
 	for i := int64(0); i < items; i++ {
-		arr.Elements[i], err = rr.readOneEntryTyped(protocol.DataType(t))
+		e, err := rr.readOneEntryTyped(protocol.DataType(t))
+		arr.Elements = append(arr.Elements, e)
 		if err != nil {
 			return &arr, fmt.Errorf("failed to read typed array entry #%d/%d: %w", i+1, items, err)
 		}
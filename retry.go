@@ -0,0 +1,126 @@
+package skytable
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/No3371/go-skytable/protocol"
+)
+
+// IdempotentAction is implemented by actions declaring whether executing them more than once
+// has the same effect and the same response as executing them once. All the actions in package action implement it:
+// actions whose repetition fails or reports other results, like SET, SSET (Overwrite), SDEL (Nil) or DEL (fewer deletions),
+// are not idempotent.
+//
+// Actions not implementing it are considered not idempotent.
+type IdempotentAction interface {
+	Idempotent() bool
+}
+
+// IsIdempotent returns true if all the actions are idempotent.
+func IsIdempotent(actions ...Action) bool {
+	for _, a := range actions {
+		ia, ok := a.(IdempotentAction)
+		if !ok || !ia.Idempotent() {
+			return false
+		}
+	}
+	return true
+}
+
+// RetryPolicy controls how queries are retried on transport errors ([ErrComu]) and RespServerError.
+//
+// A packet is retried only if all its actions are idempotent (see [IdempotentAction]),
+// or if the failed attempt never reached the wire ([ErrComu.Unsent]).
+// A packet may have been executed already when it's retried, so actions failing or reporting other results
+// when repeated are never retried once sent, e.g. SET would fail with Overwrite and SDEL with Nil.
+// SSET is not retried either, although it was first meant to be: a repeated SSET keeps the values set by the first one,
+// but it fails with Overwrite, reporting an error for a write that happened.
+//
+// Before every attempt, a closed Conn is reconnected (regardless of [Conn.EnableAutoReconnect]),
+// logging in and USE-ing the last entity again.
+//
+// Only queries executed with [Conn.BuildAndExecQuery] are retried, which is the case for most of the methods
+// except the ones sending raw packets (e.g. DDL and SYS).
+type RetryPolicy struct {
+	MaxAttempts int           // Including the first one, 1 or less disables retrying
+	MinBackoff  time.Duration // The delay before the first retry, doubled for each following one
+	MaxBackoff  time.Duration // The maximum of the delay
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  50 * time.Millisecond,
+	MaxBackoff:  time.Second,
+}
+
+// backoff returns the delay before the retry-th retry, with jitter.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	d := p.MinBackoff
+	for i := 1; i < retry && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+func (p *RetryPolicy) shouldRetry(q *QueryPacket, rp *ResponsePacket, err error) bool {
+	if err != nil {
+		var errComu ErrComu
+		if !errors.As(err, &errComu) {
+			return false
+		}
+		return errComu.Unsent() || IsIdempotent(q.actions...)
+	}
+
+	for _, r := range rp.resps {
+		if r.Value == protocol.RespServerError {
+			return IsIdempotent(q.actions...)
+		}
+	}
+
+	return false
+}
+
+// SetRetryPolicy enables retrying queries with the policy, or disables it if p is nil.
+func (c *Conn) SetRetryPolicy(p *RetryPolicy) {
+	c.retry = p
+}
+
+func (c *Conn) execWithRetry(p *QueryPacket) (rp *ResponsePacket, err error) {
+	ctx := p.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	for attempt := 1; ; attempt++ {
+		if c.isClosed() {
+			if rErr := c.reconnect(); rErr != nil {
+				err = newUnsentComuError("failed to reconnect", rErr)
+				rp = nil
+			} else {
+				rp, err = c.buildAndExecQuery(p)
+			}
+		} else {
+			rp, err = c.buildAndExecQuery(p)
+		}
+
+		if attempt >= c.retry.MaxAttempts || !c.retry.shouldRetry(p, rp, err) {
+			return rp, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return rp, err
+		case <-time.After(c.retry.backoff(attempt)):
+		}
+	}
+}
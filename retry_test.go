@@ -0,0 +1,161 @@
+package skytable_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/action"
+	"github.com/No3371/go-skytable/protocol"
+	"github.com/No3371/go-skytable/skytabletest"
+)
+
+var testRetryPolicy = &skytable.RetryPolicy{
+	MaxAttempts: 10,
+	MinBackoff:  10 * time.Millisecond,
+	MaxBackoff:  50 * time.Millisecond,
+}
+
+func TestIsIdempotent(t *testing.T) {
+	safe := []skytable.Action{
+		action.Get{}, action.MGet{}, action.Exists{}, action.LGet{}, action.USet{}, action.MKSnap{},
+	}
+	for _, a := range safe {
+		if !skytable.IsIdempotent(a) {
+			t.Errorf("expecting %T to be idempotent", a)
+		}
+	}
+
+	unsafe := []skytable.Action{
+		action.LModPush{}, action.Pop{}, action.Set{}, action.SSet{}, action.MSetA{}, action.LSet{},
+		action.CreateTable{}, action.CreateKeyspace{}, action.SDel{}, action.Del{}, action.AuthDelUser{},
+		action.MKSnap{Name: "snap"},
+	}
+	for _, a := range unsafe {
+		if skytable.IsIdempotent(a) {
+			t.Errorf("expecting %T not to be idempotent", a)
+		}
+	}
+
+	if skytable.IsIdempotent(action.Get{}, action.Pop{}) {
+		t.Error("expecting a packet with a non-idempotent action not to be idempotent")
+	}
+}
+
+func TestRetry_Idempotent(t *testing.T) {
+	ctx := context.Background()
+
+	p, err := skytabletest.NewProxy(authAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	token, _ := GetTestToken()
+	c, err := skytable.NewConnAuth(p.Addr(), func() (string, string, error) {
+		return testUserName, token, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.SetRetryPolicy(testRetryPolicy)
+
+	k := "test_retry_idempotent"
+	c.Del(ctx, []string{k})
+	if err := c.Set(ctx, k, "v"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The retry has to login again
+	p.TruncateNextResponse(3)
+	v, err := c.GetBytes(ctx, k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(v) != "v" {
+		t.Fatalf("expecting v but got %s", v)
+	}
+}
+
+func TestRetry_NotIdempotent(t *testing.T) {
+	ctx := context.Background()
+
+	p, err := skytabletest.NewProxy(noAuthAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	c, err := skytable.NewConn(p.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.SetRetryPolicy(testRetryPolicy)
+
+	if err := c.Use(ctx, "default:default"); err != nil {
+		t.Fatal(err)
+	}
+
+	p.TruncateNextResponse(3)
+	_, err = c.Pop(ctx, "test_retry_not_idempotent")
+
+	var errComu skytable.ErrComu
+	if !errors.As(err, &errComu) {
+		t.Fatalf("expecting ErrComu but got %v", err)
+	}
+	if errComu.Unsent() {
+		t.Fatal("expecting the packet to be sent")
+	}
+}
+
+func TestRetry_Unsent(t *testing.T) {
+	ctx := context.Background()
+
+	p, err := skytabletest.NewProxy(noAuthAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := skytable.NewConn(p.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.SetRetryPolicy(testRetryPolicy)
+
+	// Break the conn, and make the following reconnections fail until a server is up again
+	addr := p.Addr()
+	p.Close()
+	if err := c.Heya(ctx, ""); err == nil {
+		t.Fatal("expecting the conn to be dropped")
+	}
+
+	up := make(chan *skytabletest.Server)
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		srv, err := skytabletest.NewServer(skytabletest.Options{Addr: addr.String()})
+		if err != nil {
+			t.Error(err)
+		}
+		up <- srv
+	}()
+
+	// Not idempotent, but retried because the failed reconnections never sent the packet
+	resp, err := c.Pop(ctx, "test_retry_unsent")
+	srv := <-up
+	if srv == nil {
+		t.FailNow()
+	}
+	defer srv.Close()
+
+	if err != nil {
+		t.Fatalf("expecting the packet to be retried until the server is up but got %v", err)
+	}
+	if resp.Value != protocol.RespNil {
+		t.Fatalf("expecting Nil from the fresh server but got %v", resp.Value)
+	}
+}
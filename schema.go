@@ -0,0 +1,240 @@
+package skytable
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/No3371/go-skytable/protocol"
+)
+
+// ErrSchemaMismatch is returned by EnsureSchema when existing tables differ from the schema.
+var ErrSchemaMismatch = errors.New("schema mismatch")
+
+// Schema declares the keyspaces and the tables a service needs, see [EnsureSchema].
+type Schema struct {
+	Keyspaces []KeyspaceSchema
+}
+
+type KeyspaceSchema struct {
+	Name   string
+	Tables []TableSchema
+}
+
+type TableSchema struct {
+	Name  string                    // Without the keyspace
+	Model protocol.ModelDescription // Like protocol.KeyMapDescription, or protocol.TypedKeyMapDescription for list tables
+}
+
+type EnsureSchemaOptions struct {
+	DryRun        bool // Only return the plan, nothing is changed
+	DropExtra     bool // Drop the tables of the declared keyspaces which are not declared, "default:default" excepted
+	AllowMismatch bool // Apply the plan even if existing tables differ from the schema, instead of returning ErrSchemaMismatch
+}
+
+type SchemaChangeKind byte
+
+const (
+	SchemaCreateKeyspace SchemaChangeKind = iota
+	SchemaCreateTable
+	SchemaDropTable
+	// The table exists with another model, it's left as it is
+	SchemaMismatch
+)
+
+type SchemaChange struct {
+	Kind SchemaChangeKind
+	Path string                    // The keyspace, or the table as "keyspace:table"
+	Want protocol.ModelDescription // The declared model, of SchemaCreateTable and SchemaMismatch
+	Got  protocol.ModelDescription // The existing model, of SchemaMismatch
+}
+
+func (c SchemaChange) String() string {
+	switch c.Kind {
+	case SchemaCreateKeyspace:
+		return "CREATE KEYSPACE " + c.Path
+	case SchemaCreateTable:
+		return "CREATE TABLE " + c.Path + " " + describeModel(c.Want)
+	case SchemaDropTable:
+		return "DROP TABLE " + c.Path
+	case SchemaMismatch:
+		return fmt.Sprintf("MISMATCH %s: declared %s, found %s", c.Path, describeModel(c.Want), describeModel(c.Got))
+	default:
+		return fmt.Sprintf("unknown change of %s", c.Path)
+	}
+}
+
+func describeModel(d protocol.ModelDescription) string {
+	if d == nil {
+		return "unknown model"
+	}
+	return strings.TrimSpace(d.Model() + " " + d.Properties())
+}
+
+// SchemaPlan is the list of changes EnsureSchema applies, or would apply in a dry run.
+type SchemaPlan []SchemaChange
+
+// String returns the changes, one per line.
+func (p SchemaPlan) String() string {
+	var b strings.Builder
+	for _, c := range p {
+		b.WriteString(c.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Mismatches returns the tables differing from the schema.
+func (p SchemaPlan) Mismatches() []SchemaChange {
+	var mismatches []SchemaChange
+	for _, c := range p {
+		if c.Kind == SchemaMismatch {
+			mismatches = append(mismatches, c)
+		}
+	}
+	return mismatches
+}
+
+// EnsureSchema compares the keyspaces and tables of the server with the schema, then creates the missing ones.
+//
+// Existing tables whose model or volatility differ from the schema are never altered: unless AllowMismatch is set,
+// EnsureSchema returns ErrSchemaMismatch without applying anything. The returned plan lists the changes,
+// the mismatches included, even when an error is returned.
+//
+// Keyspaces and tables created concurrently by other instances are not considered errors.
+func EnsureSchema(ctx context.Context, c Skytable, schema Schema, opts EnsureSchemaOptions) (SchemaPlan, error) {
+	plan, err := planSchema(ctx, c, schema, opts)
+	if err != nil {
+		return nil, fmt.Errorf("EnsureSchema(): %w", err)
+	}
+
+	if mismatches := plan.Mismatches(); len(mismatches) > 0 && !opts.AllowMismatch {
+		paths := make([]string, len(mismatches))
+		for i, m := range mismatches {
+			paths[i] = m.Path
+		}
+		return plan, fmt.Errorf("EnsureSchema(): %w: %s", ErrSchemaMismatch, strings.Join(paths, ", "))
+	}
+
+	if opts.DryRun {
+		return plan, nil
+	}
+
+	for _, change := range plan {
+		err = applySchemaChange(ctx, c, change)
+		if err != nil {
+			return plan, fmt.Errorf("EnsureSchema(): %s: %w", change, err)
+		}
+	}
+
+	return plan, nil
+}
+
+// validateSchema returns an ErrInvalidUsage if a keyspace or a table has no name, or a table has no model.
+func validateSchema(schema Schema) error {
+	for _, ks := range schema.Keyspaces {
+		if ks.Name == "" {
+			return NewUsageError("a keyspace of the schema has no name", nil)
+		}
+		for _, t := range ks.Tables {
+			if t.Name == "" {
+				return NewUsageError(fmt.Sprintf("a table of %s has no name", ks.Name), nil)
+			}
+			if t.Model == nil {
+				return NewUsageError(fmt.Sprintf("%s:%s has no model", ks.Name, t.Name), nil)
+			}
+		}
+	}
+
+	return nil
+}
+
+func planSchema(ctx context.Context, c Skytable, schema Schema, opts EnsureSchemaOptions) (SchemaPlan, error) {
+	err := validateSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	arr, err := c.InspectKeyspaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keyspaces := make(map[string]bool, len(arr.Elements))
+	for _, name := range catalogNames(arr) {
+		keyspaces[name] = true
+	}
+
+	var plan SchemaPlan
+	for _, ks := range schema.Keyspaces {
+		var tables []string
+		existing := make(map[string]bool)
+		if keyspaces[ks.Name] {
+			arr, err := c.InspectKeyspace(ctx, ks.Name)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", ks.Name, err)
+			}
+			tables = catalogNames(arr)
+			for _, name := range tables {
+				existing[name] = true
+			}
+		} else {
+			plan = append(plan, SchemaChange{Kind: SchemaCreateKeyspace, Path: ks.Name})
+		}
+
+		declared := make(map[string]bool, len(ks.Tables))
+		for _, t := range ks.Tables {
+			path := ks.Name + ":" + t.Name
+			declared[t.Name] = true
+
+			if !existing[t.Name] {
+				plan = append(plan, SchemaChange{Kind: SchemaCreateTable, Path: path, Want: t.Model})
+				continue
+			}
+
+			got, err := c.InspectTable(ctx, path)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			if !protocol.SameModel(got, t.Model) {
+				plan = append(plan, SchemaChange{Kind: SchemaMismatch, Path: path, Want: t.Model, Got: got})
+			}
+		}
+
+		if !opts.DropExtra {
+			continue
+		}
+		for _, name := range tables {
+			if !declared[name] && !(ks.Name == "default" && name == "default") {
+				plan = append(plan, SchemaChange{Kind: SchemaDropTable, Path: ks.Name + ":" + name})
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+func applySchemaChange(ctx context.Context, c Skytable, change SchemaChange) error {
+	var err error
+	switch change.Kind {
+	case SchemaCreateKeyspace:
+		err = c.CreateKeyspace(ctx, change.Path)
+	case SchemaCreateTable:
+		err = c.CreateTable(ctx, change.Path, change.Want)
+	case SchemaDropTable:
+		err = c.DropTable(ctx, change.Path)
+	}
+
+	var errStr *protocol.ErrorStringResponse
+	if errors.As(err, &errStr) {
+		switch {
+		case errStr.Errstr == protocol.ErrStr_AlreadyExists && change.Kind != SchemaDropTable:
+			// Created meanwhile
+			return nil
+		case errStr.Errstr == protocol.ErrStr_ContainerNotFound && change.Kind == SchemaDropTable:
+			// Dropped meanwhile
+			return nil
+		}
+	}
+	return err
+}
@@ -0,0 +1,172 @@
+package skytable_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/protocol"
+	"github.com/No3371/go-skytable/skytabletest"
+)
+
+var testSchema = skytable.Schema{Keyspaces: []skytable.KeyspaceSchema{
+	{Name: "app", Tables: []skytable.TableSchema{
+		{Name: "users", Model: protocol.KeyMapDescription{KeyType: protocol.DDLDataTypes_String, ValueType: protocol.DDLDataTypes_BinaryString}},
+		{Name: "sessions", Model: protocol.KeyMapDescription{KeyType: protocol.DDLDataTypes_String, ValueType: protocol.DDLDataTypes_String, Volatile: true}},
+		{Name: "queues", Model: protocol.TypedKeyMapDescription{KeyType: protocol.DDLTypeOf(protocol.DDLDataTypes_String), ValueType: protocol.ListOf(protocol.DDLTypeOf(protocol.DDLDataTypes_String))}},
+	}},
+	{Name: "default", Tables: []skytable.TableSchema{
+		{Name: "default", Model: protocol.KeyMapDescription{KeyType: protocol.DDLDataTypes_BinaryString, ValueType: protocol.DDLDataTypes_BinaryString}},
+	}},
+}}
+
+func newSchemaTestConn(t *testing.T) *skytable.Conn {
+	srv, err := skytabletest.NewServer(skytabletest.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { srv.Close() })
+
+	c, err := skytable.NewConn(srv.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	return c
+}
+
+func TestEnsureSchema(t *testing.T) {
+	ctx := context.Background()
+	c := newSchemaTestConn(t)
+
+	want := "CREATE KEYSPACE app\nCREATE TABLE app:users keymap(str,binstr)\nCREATE TABLE app:sessions keymap(str,str) volatile\nCREATE TABLE app:queues keymap(str,list<str>)\n"
+
+	plan, err := skytable.EnsureSchema(ctx, c, testSchema, skytable.EnsureSchemaOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.String() != want {
+		t.Fatalf("dry run plan:\n%s\nwant:\n%s", plan, want)
+	}
+	if _, err := c.InspectKeyspace(ctx, "app"); err == nil {
+		t.Fatal("the dry run created keyspace app")
+	}
+
+	plan, err = skytable.EnsureSchema(ctx, c, testSchema, skytable.EnsureSchemaOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.String() != want {
+		t.Fatalf("plan:\n%s\nwant:\n%s", plan, want)
+	}
+
+	for _, table := range testSchema.Keyspaces[0].Tables {
+		got, err := c.InspectTable(ctx, "app:"+table.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !protocol.SameModel(got, table.Model) {
+			t.Errorf("app:%s is %v, want %v", table.Name, got, table.Model)
+		}
+	}
+
+	plan, err = skytable.EnsureSchema(ctx, c, testSchema, skytable.EnsureSchemaOptions{})
+	if err != nil || len(plan) != 0 {
+		t.Fatalf("expecting nothing to do on the second run but got %v, %v", plan, err)
+	}
+}
+
+func TestEnsureSchema_Mismatch(t *testing.T) {
+	ctx := context.Background()
+	c := newSchemaTestConn(t)
+
+	if _, err := skytable.EnsureSchema(ctx, c, testSchema, skytable.EnsureSchemaOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	changed := skytable.Schema{Keyspaces: []skytable.KeyspaceSchema{
+		{Name: "app", Tables: []skytable.TableSchema{
+			{Name: "users", Model: protocol.KeyMapDescription{KeyType: protocol.DDLDataTypes_String, ValueType: protocol.DDLDataTypes_String}},
+			{Name: "sessions", Model: protocol.KeyMapDescription{KeyType: protocol.DDLDataTypes_String, ValueType: protocol.DDLDataTypes_String}},
+			{Name: "posts", Model: protocol.KeyMapDescription{KeyType: protocol.DDLDataTypes_String, ValueType: protocol.DDLDataTypes_String}},
+		}},
+	}}
+
+	plan, err := skytable.EnsureSchema(ctx, c, changed, skytable.EnsureSchemaOptions{})
+	if !errors.Is(err, skytable.ErrSchemaMismatch) {
+		t.Fatalf("expecting ErrSchemaMismatch but got %v", err)
+	}
+	want := "MISMATCH app:users: declared keymap(str,str), found keymap(str,binstr)\n" +
+		"MISMATCH app:sessions: declared keymap(str,str), found keymap(str,str) volatile\n" +
+		"CREATE TABLE app:posts keymap(str,str)\n"
+	if plan.String() != want {
+		t.Fatalf("plan:\n%s\nwant:\n%s", plan, want)
+	}
+	if _, err := c.InspectTable(ctx, "app:posts"); err == nil {
+		t.Fatal("expecting nothing to be applied on mismatches")
+	}
+
+	_, err = skytable.EnsureSchema(ctx, c, changed, skytable.EnsureSchemaOptions{AllowMismatch: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.InspectTable(ctx, "app:posts"); err != nil {
+		t.Fatalf("expecting app:posts to be created with AllowMismatch but got %v", err)
+	}
+}
+
+func TestEnsureSchema_DropExtra(t *testing.T) {
+	ctx := context.Background()
+	c := newSchemaTestConn(t)
+
+	if _, err := skytable.EnsureSchema(ctx, c, testSchema, skytable.EnsureSchemaOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	reduced := skytable.Schema{Keyspaces: []skytable.KeyspaceSchema{
+		{Name: "app", Tables: testSchema.Keyspaces[0].Tables[:1]},
+		{Name: "default"},
+	}}
+
+	plan, err := skytable.EnsureSchema(ctx, c, reduced, skytable.EnsureSchemaOptions{})
+	if err != nil || len(plan) != 0 {
+		t.Fatalf("expecting extra tables to be kept without DropExtra but got %v, %v", plan, err)
+	}
+
+	plan, err = skytable.EnsureSchema(ctx, c, reduced, skytable.EnsureSchemaOptions{DropExtra: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.String() != "DROP TABLE app:queues\nDROP TABLE app:sessions\n" {
+		t.Fatalf("unexpected plan:\n%s", plan)
+	}
+
+	tables, err := c.InspectKeyspace(ctx, "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables.Elements) != 1 || tables.Elements[0] != "users" {
+		t.Errorf("expecting only app:users to be left but got %v", tables.Elements)
+	}
+}
+
+func TestEnsureSchema_Invalid(t *testing.T) {
+	ctx := context.Background()
+	c := newSchemaTestConn(t)
+
+	invalid := skytable.Schema{Keyspaces: []skytable.KeyspaceSchema{
+		{Name: "app", Tables: []skytable.TableSchema{{Name: "users"}}},
+	}}
+
+	var errUsage skytable.ErrInvalidUsage
+	if _, err := skytable.EnsureSchema(ctx, c, invalid, skytable.EnsureSchemaOptions{DryRun: true}); !errors.As(err, &errUsage) {
+		t.Fatalf("expecting ErrInvalidUsage for a table without model but got %v", err)
+	}
+
+	change := skytable.SchemaChange{Kind: skytable.SchemaCreateTable, Path: "app:users"}
+	if change.String() != "CREATE TABLE app:users unknown model" {
+		t.Errorf("unexpected change: %s", change)
+	}
+}
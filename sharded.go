@@ -0,0 +1,316 @@
+package skytable
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/No3371/go-skytable/action"
+	"github.com/No3371/go-skytable/protocol"
+)
+
+// HashFunc hashes keys and virtual nodes onto the ring of a [ShardedClient].
+type HashFunc func(key string) uint64
+
+// DefaultHash is the default HashFunc: FNV-1a, finalized like MurmurHash3
+// so keys only differing by their last bytes are spread over the ring.
+func DefaultHash(key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	x := h.Sum64()
+
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+// Strictness controls how SSET, SUPDATE and SDEL behave when their keys are spread over multiple shards,
+// as Skytable only guarantees their all-or-nothing semantics within an instance.
+type Strictness byte
+
+const (
+	// Each shard applies its part all-or-nothing, but some shards may succeed while others fail (see [ErrShards]).
+	StrictPerShard Strictness = iota
+	// The keys are checked on all the shards first (EXISTS), and nothing is sent if the action would fail on any of them.
+	// It's best-effort: the keys may change between the check and the action.
+	StrictCheckAll
+	// The action is rejected with [ErrInvalidUsage] if the keys are spread over multiple shards.
+	StrictSingleShard
+)
+
+type Shard struct {
+	Name string // Identifies the shard on the ring, keep it stable so keys stay on their shard
+	Pool *ConnPool
+}
+
+type ShardedOptions struct {
+	VirtualNodes int      // Points per shard on the ring, 160 if 0
+	Hash         HashFunc // DefaultHash if nil
+	Strictness   Strictness
+}
+
+// ShardError is a failure of a shard in [ErrShards].
+type ShardError struct {
+	Shard string
+	Err   error
+}
+
+// ErrShards is returned when a multi-key call failed on some of the shards,
+// the parts sent to the other shards may have succeeded.
+type ErrShards []ShardError
+
+func (err ErrShards) Error() string {
+	msgs := make([]string, len(err))
+	for i, e := range err {
+		msgs[i] = fmt.Sprintf("%s: %s", e.Shard, e.Err)
+	}
+	return "shards failed: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the error of the first failed shard.
+func (err ErrShards) Unwrap() error {
+	return err[0].Err
+}
+
+type ringPoint struct {
+	hash  uint64
+	shard int
+}
+
+// ShardedClient spreads keys over multiple [ConnPool]s with a consistent-hash ring.
+//
+// Single-key methods are sent to the shard of the key. Multi-key methods are split per shard,
+// sent in parallel, and their results are merged back in input order (counts are summed).
+// Keyless methods are either sent to all the shards (HEYA, AUTH LOGIN/LOGOUT, USE, DDL, FLUSHDB, MKSNAP),
+// aggregated (DBSIZE, LSKEYS, SYS METRIC) or sent to the first shard (other AUTH actions, INSPECT, SYS INFO, WHEREAMI).
+//
+// Raw packets can't be routed, use [ShardedClient.ShardFor] to execute them on the shard of a key.
+type ShardedClient struct {
+	shards []Shard
+	ring   []ringPoint
+	opts   ShardedOptions
+}
+
+// NewShardedClient creates a ShardedClient over the shards, which must have distinct names.
+// An ErrInvalidUsage is returned if there's no shard or the names are not distinct.
+func NewShardedClient(shards []Shard, opts ShardedOptions) (*ShardedClient, error) {
+	if len(shards) == 0 {
+		return nil, NewUsageError("NewShardedClient(): no shard", nil)
+	}
+	names := make(map[string]struct{}, len(shards))
+	for _, s := range shards {
+		if _, dup := names[s.Name]; dup {
+			return nil, NewUsageError(fmt.Sprintf("NewShardedClient(): duplicate shard name %q", s.Name), nil)
+		}
+		names[s.Name] = struct{}{}
+	}
+
+	if opts.VirtualNodes <= 0 {
+		opts.VirtualNodes = 160
+	}
+	if opts.Hash == nil {
+		opts.Hash = DefaultHash
+	}
+
+	c := &ShardedClient{
+		shards: shards,
+		ring:   make([]ringPoint, 0, len(shards)*opts.VirtualNodes),
+		opts:   opts,
+	}
+
+	for i, s := range shards {
+		for v := 0; v < opts.VirtualNodes; v++ {
+			c.ring = append(c.ring, ringPoint{
+				hash:  opts.Hash(s.Name + "#" + strconv.Itoa(v)),
+				shard: i,
+			})
+		}
+	}
+
+	sort.Slice(c.ring, func(i, j int) bool {
+		return c.ring[i].hash < c.ring[j].hash
+	})
+
+	return c, nil
+}
+
+// Shards returns the shards.
+func (c *ShardedClient) Shards() []Shard {
+	return c.shards
+}
+
+// ShardFor returns the pool of the shard the key belongs to.
+func (c *ShardedClient) ShardFor(key string) *ConnPool {
+	return c.shards[c.shardOf(key)].Pool
+}
+
+func (c *ShardedClient) shardOf(key string) int {
+	h := c.opts.Hash(key)
+	i := sort.Search(len(c.ring), func(i int) bool {
+		return c.ring[i].hash >= h
+	})
+	if i == len(c.ring) {
+		i = 0
+	}
+	return c.ring[i].shard
+}
+
+type shardGroup struct {
+	shard int
+	idx   []int // Indices in the input
+}
+
+// split groups the indices of the keys by shard.
+func (c *ShardedClient) split(n int, keyAt func(i int) string) []shardGroup {
+	byShard := make(map[int]int) // shard -> index in groups
+	var groups []shardGroup
+	for i := 0; i < n; i++ {
+		s := c.shardOf(keyAt(i))
+		g, found := byShard[s]
+		if !found {
+			g = len(groups)
+			byShard[s] = g
+			groups = append(groups, shardGroup{shard: s})
+		}
+		groups[g].idx = append(groups[g].idx, i)
+	}
+	return groups
+}
+
+func (c *ShardedClient) splitKeys(keys []string) []shardGroup {
+	return c.split(len(keys), func(i int) string { return keys[i] })
+}
+
+func (c *ShardedClient) splitEntries(entries []action.KVPair) []shardGroup {
+	return c.split(len(entries), func(i int) string { return entries[i].K })
+}
+
+// onShards calls f for every group in parallel, returning the results in the order of the groups.
+func onShards[T any](c *ShardedClient, groups []shardGroup, f func(p *ConnPool, idx []int) (T, error)) ([]T, error) {
+	results := make([]T, len(groups))
+	errs := make([]error, len(groups))
+
+	wg := sync.WaitGroup{}
+	for i, g := range groups {
+		wg.Add(1)
+		go func(i int, g shardGroup) {
+			defer wg.Done()
+			results[i], errs[i] = f(c.shards[g.shard].Pool, g.idx)
+		}(i, g)
+	}
+	wg.Wait()
+
+	var failed ErrShards
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, ShardError{Shard: c.shards[groups[i].shard].Name, Err: err})
+		}
+	}
+	if failed != nil {
+		return results, failed
+	}
+
+	return results, nil
+}
+
+// onAllShards calls f for every shard in parallel.
+func onAllShards[T any](c *ShardedClient, f func(p *ConnPool) (T, error)) ([]T, error) {
+	groups := make([]shardGroup, len(c.shards))
+	for i := range c.shards {
+		groups[i] = shardGroup{shard: i}
+	}
+	return onShards(c, groups, func(p *ConnPool, _ []int) (T, error) {
+		return f(p)
+	})
+}
+
+func sum(counts []uint64) (total uint64) {
+	for _, n := range counts {
+		total += n
+	}
+	return total
+}
+
+func pick[T any](s []T, idx []int) []T {
+	picked := make([]T, len(idx))
+	for i, j := range idx {
+		picked[i] = s[j]
+	}
+	return picked
+}
+
+func entryKeys(entries []action.KVPair) []string {
+	keys := make([]string, len(entries))
+	for i, e := range entries {
+		keys[i] = e.K
+	}
+	return keys
+}
+
+// merge puts the elements of the arrays returned for the groups back in input order.
+// The elements of failed groups are left nil.
+func merge(n int, groups []shardGroup, arrays []*protocol.TypedArray) *protocol.TypedArray {
+	merged := &protocol.TypedArray{}
+	merged.Elements = make([]interface{}, n)
+	for i, a := range arrays {
+		if a == nil {
+			continue
+		}
+		merged.ArrayType = a.ArrayType
+		merged.ElementType = a.ElementType
+		for j, e := range a.Elements {
+			if j < len(groups[i].idx) {
+				merged.Elements[groups[i].idx[j]] = e
+			}
+		}
+	}
+	return merged
+}
+
+func (c *ShardedClient) first() *ConnPool {
+	return c.shards[0].Pool
+}
+
+// onAll calls f with every shard in parallel.
+func (c *ShardedClient) onAll(f func(p *ConnPool) error) error {
+	_, err := onAllShards(c, func(p *ConnPool) (struct{}, error) {
+		return struct{}{}, f(p)
+	})
+	return err
+}
+
+// checkStrict applies the Strictness before a SSET (exist = false), SUPDATE or SDEL (exist = true) spanning multiple shards.
+func (c *ShardedClient) checkStrict(ctx context.Context, groups []shardGroup, keys []string, exist bool) error {
+	if len(groups) < 2 {
+		return nil
+	}
+
+	switch c.opts.Strictness {
+	case StrictSingleShard:
+		return NewUsageError("the keys are spread over multiple shards", nil)
+	case StrictCheckAll:
+		counts, err := onShards(c, groups, func(p *ConnPool, idx []int) (uint64, error) {
+			return p.Exists(ctx, pick(keys, idx))
+		})
+		if err != nil {
+			return err
+		}
+
+		existing := sum(counts)
+		if exist && existing != uint64(len(keys)) {
+			return protocol.ErrCodeNil
+		}
+		if !exist && existing != 0 {
+			return protocol.ErrCodeOverwriteError
+		}
+	}
+
+	return nil
+}
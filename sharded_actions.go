@@ -0,0 +1,460 @@
+package skytable
+
+import (
+	"context"
+
+	"github.com/No3371/go-skytable/action"
+	"github.com/No3371/go-skytable/protocol"
+	"github.com/No3371/go-skytable/response"
+)
+
+// https://docs.skytable.io/actions/heya
+func (c *ShardedClient) Heya(ctx context.Context, echo string) error {
+	return c.onAll(func(p *ConnPool) error {
+		return p.Heya(ctx, echo)
+	})
+}
+
+// https://docs.skytable.io/actions/auth#login
+func (c *ShardedClient) AuthLogin(ctx context.Context, authProvider AuthProvider) error {
+	return c.onAll(func(p *ConnPool) error {
+		return p.AuthLogin(ctx, authProvider)
+	})
+}
+
+// https://docs.skytable.io/actions/auth#logout
+func (c *ShardedClient) AuthLogout(ctx context.Context) error {
+	return c.onAll(func(p *ConnPool) error {
+		return p.AuthLogout(ctx)
+	})
+}
+
+// https://docs.skytable.io/actions/auth#claim
+func (c *ShardedClient) AuthClaim(ctx context.Context, originKey string) (string, error) {
+	return c.first().AuthClaim(ctx, originKey)
+}
+
+// https://docs.skytable.io/actions/auth#adduser
+func (c *ShardedClient) AuthAddUser(ctx context.Context, username string) (string, error) {
+	return c.first().AuthAddUser(ctx, username)
+}
+
+// https://docs.skytable.io/actions/auth#deluser
+func (c *ShardedClient) AuthDelUser(ctx context.Context, username string) error {
+	return c.first().AuthDelUser(ctx, username)
+}
+
+// https://docs.skytable.io/actions/auth#restore
+//
+// If provided `originKey` is "", it'll be omitted in the sent command
+func (c *ShardedClient) AuthRestore(ctx context.Context, originKey string, username string) (string, error) {
+	return c.first().AuthRestore(ctx, originKey, username)
+}
+
+// https://docs.skytable.io/actions/auth#listuser
+func (c *ShardedClient) AuthListUser(ctx context.Context) (*protocol.TypedArray, error) {
+	return c.first().AuthListUser(ctx)
+}
+
+// https://docs.skytable.io/actions/auth#whoami
+func (c *ShardedClient) AuthWhoAmI(ctx context.Context) (string, error) {
+	return c.first().AuthWhoAmI(ctx)
+}
+
+// https://docs.skytable.io/actions/exists
+func (c *ShardedClient) Exists(ctx context.Context, keys []string) (uint64, error) {
+	counts, err := onShards(c, c.splitKeys(keys), func(p *ConnPool, idx []int) (uint64, error) {
+		return p.Exists(ctx, pick(keys, idx))
+	})
+	return sum(counts), err
+}
+
+// https://docs.skytable.io/actions/del
+func (c *ShardedClient) Del(ctx context.Context, keys []string) (uint64, error) {
+	counts, err := onShards(c, c.splitKeys(keys), func(p *ConnPool, idx []int) (uint64, error) {
+		return p.Del(ctx, pick(keys, idx))
+	})
+	return sum(counts), err
+}
+
+// https://docs.skytable.io/actions/sdel
+//
+// All-or-nothing across shards depends on the [Strictness].
+func (c *ShardedClient) SDel(ctx context.Context, keys []string) error {
+	groups := c.splitKeys(keys)
+	if err := c.checkStrict(ctx, groups, keys, true); err != nil {
+		return err
+	}
+
+	_, err := onShards(c, groups, func(p *ConnPool, idx []int) (struct{}, error) {
+		return struct{}{}, p.SDel(ctx, pick(keys, idx))
+	})
+	return err
+}
+
+// https://docs.skytable.io/actions/get
+func (c *ShardedClient) Get(ctx context.Context, key string) (response.ResponseEntry, error) {
+	return c.ShardFor(key).Get(ctx, key)
+}
+
+// a strict version of [Get] that only success if the value is stored as String in Skytable.
+func (c *ShardedClient) GetString(ctx context.Context, key string) (string, error) {
+	return c.ShardFor(key).GetString(ctx, key)
+}
+
+// a strict version of [Get] that only success if the value is stored as BinaryString in Skytable.
+func (c *ShardedClient) GetBytes(ctx context.Context, key string) ([]byte, error) {
+	return c.ShardFor(key).GetBytes(ctx, key)
+}
+
+// https://docs.skytable.io/actions/mget
+//
+// If some shards fail, the elements of their keys are nil and the error is an [ErrShards].
+func (c *ShardedClient) MGet(ctx context.Context, keys []string) (*protocol.TypedArray, error) {
+	groups := c.splitKeys(keys)
+	arrays, err := onShards(c, groups, func(p *ConnPool, idx []int) (*protocol.TypedArray, error) {
+		return p.MGet(ctx, pick(keys, idx))
+	})
+	return merge(len(keys), groups, arrays), err
+}
+
+// https://docs.skytable.io/actions/pop
+func (c *ShardedClient) Pop(ctx context.Context, key string) (response.ResponseEntry, error) {
+	return c.ShardFor(key).Pop(ctx, key)
+}
+
+// https://docs.skytable.io/actions/mpop
+//
+// If some shards fail, the elements of their keys are nil and the error is an [ErrShards].
+func (c *ShardedClient) MPop(ctx context.Context, keys []string) (*protocol.TypedArray, error) {
+	groups := c.splitKeys(keys)
+	arrays, err := onShards(c, groups, func(p *ConnPool, idx []int) (*protocol.TypedArray, error) {
+		return p.MPop(ctx, pick(keys, idx))
+	})
+	return merge(len(keys), groups, arrays), err
+}
+
+// https://docs.skytable.io/actions/set
+func (c *ShardedClient) Set(ctx context.Context, key string, value any) error {
+	return c.ShardFor(key).Set(ctx, key, value)
+}
+
+// https://docs.skytable.io/actions/mset
+func (c *ShardedClient) MSetB(ctx context.Context, keys []string, values []any) (uint64, error) {
+	if len(keys) != len(values) {
+		return 0, NewUsageError("MSetB(): keys and values must have the same length", nil)
+	}
+
+	counts, err := onShards(c, c.splitKeys(keys), func(p *ConnPool, idx []int) (uint64, error) {
+		return p.MSetB(ctx, pick(keys, idx), pick(values, idx))
+	})
+	return sum(counts), err
+}
+
+// https://docs.skytable.io/actions/mset
+func (c *ShardedClient) MSet(ctx context.Context, entries []action.KVPair) (uint64, error) {
+	counts, err := onShards(c, c.splitEntries(entries), func(p *ConnPool, idx []int) (uint64, error) {
+		return p.MSet(ctx, pick(entries, idx))
+	})
+	return sum(counts), err
+}
+
+// https://docs.skytable.io/actions/sset
+//
+// All-or-nothing across shards depends on the [Strictness].
+func (c *ShardedClient) SSet(ctx context.Context, entries []action.KVPair) error {
+	groups := c.splitEntries(entries)
+	if err := c.checkStrict(ctx, groups, entryKeys(entries), false); err != nil {
+		return err
+	}
+
+	_, err := onShards(c, groups, func(p *ConnPool, idx []int) (struct{}, error) {
+		return struct{}{}, p.SSet(ctx, pick(entries, idx))
+	})
+	return err
+}
+
+func (c *ShardedClient) USet(ctx context.Context, entries ...action.KVPair) (uint64, error) {
+	counts, err := onShards(c, c.splitEntries(entries), func(p *ConnPool, idx []int) (uint64, error) {
+		return p.USet(ctx, pick(entries, idx)...)
+	})
+	return sum(counts), err
+}
+
+// https://docs.skytable.io/actions/update
+func (c *ShardedClient) Update(ctx context.Context, key string, value any) error {
+	return c.ShardFor(key).Update(ctx, key, value)
+}
+
+// https://docs.skytable.io/actions/mupdate
+func (c *ShardedClient) MUpdate(ctx context.Context, entries []action.KVPair) (uint64, error) {
+	counts, err := onShards(c, c.splitEntries(entries), func(p *ConnPool, idx []int) (uint64, error) {
+		return p.MUpdate(ctx, pick(entries, idx))
+	})
+	return sum(counts), err
+}
+
+// https://docs.skytable.io/actions/supdate
+//
+// All-or-nothing across shards depends on the [Strictness].
+func (c *ShardedClient) SUpdate(ctx context.Context, entries []action.KVPair) error {
+	groups := c.splitEntries(entries)
+	if err := c.checkStrict(ctx, groups, entryKeys(entries), true); err != nil {
+		return err
+	}
+
+	_, err := onShards(c, groups, func(p *ConnPool, idx []int) (struct{}, error) {
+		return struct{}{}, p.SUpdate(ctx, pick(entries, idx))
+	})
+	return err
+}
+
+// https://docs.skytable.io/actions/lget#lget
+func (c *ShardedClient) LGet(ctx context.Context, listName string) (*protocol.TypedArray, error) {
+	return c.ShardFor(listName).LGet(ctx, listName)
+}
+
+// https://docs.skytable.io/actions/lget#limit
+func (c *ShardedClient) LGetLimit(ctx context.Context, listName string, limit uint64) (*protocol.TypedArray, error) {
+	return c.ShardFor(listName).LGetLimit(ctx, listName, limit)
+}
+
+// https://docs.skytable.io/actions/lget#len
+func (c *ShardedClient) LGetLen(ctx context.Context, listName string) (uint64, error) {
+	return c.ShardFor(listName).LGetLen(ctx, listName)
+}
+
+// https://docs.skytable.io/actions/lget#valueat
+func (c *ShardedClient) LGetValueAt(ctx context.Context, listName string, index uint64) (response.ResponseEntry, error) {
+	return c.ShardFor(listName).LGetValueAt(ctx, listName, index)
+}
+
+// https://docs.skytable.io/actions/lget#first
+func (c *ShardedClient) LGetFirst(ctx context.Context, listName string) (response.ResponseEntry, error) {
+	return c.ShardFor(listName).LGetFirst(ctx, listName)
+}
+
+// https://docs.skytable.io/actions/lget#last
+func (c *ShardedClient) LGetLast(ctx context.Context, listName string) (response.ResponseEntry, error) {
+	return c.ShardFor(listName).LGetLast(ctx, listName)
+}
+
+// https://docs.skytable.io/actions/lget#range
+//
+// If provided `to` is 0, it's omitted in the sent command.
+func (c *ShardedClient) LGetRange(ctx context.Context, listName string, from uint64, to uint64) (*protocol.TypedArray, error) {
+	return c.ShardFor(listName).LGetRange(ctx, listName, from, to)
+}
+
+// https://docs.skytable.io/actions/lmod#push
+func (c *ShardedClient) LModPush(ctx context.Context, listName string, elements []any) error {
+	return c.ShardFor(listName).LModPush(ctx, listName, elements)
+}
+
+// https://docs.skytable.io/actions/lmod#insert
+func (c *ShardedClient) LModInsert(ctx context.Context, listName string, index uint64, element any) error {
+	return c.ShardFor(listName).LModInsert(ctx, listName, index, element)
+}
+
+// https://docs.skytable.io/actions/lmod#pop
+func (c *ShardedClient) LModPop(ctx context.Context, listName string) (response.ResponseEntry, error) {
+	return c.ShardFor(listName).LModPop(ctx, listName)
+}
+
+// https://docs.skytable.io/actions/lmod#pop
+func (c *ShardedClient) LModPopIndex(ctx context.Context, listName string, index uint64) (response.ResponseEntry, error) {
+	return c.ShardFor(listName).LModPopIndex(ctx, listName, index)
+}
+
+// https://docs.skytable.io/actions/lmod#remove
+func (c *ShardedClient) LModRemove(ctx context.Context, listName string, index uint64) error {
+	return c.ShardFor(listName).LModRemove(ctx, listName, index)
+}
+
+// https://docs.skytable.io/actions/lmod#clear
+func (c *ShardedClient) LModClear(ctx context.Context, listName string) error {
+	return c.ShardFor(listName).LModClear(ctx, listName)
+}
+
+// https://docs.skytable.io/actions/lset
+//
+// If `elements` is nil, it's omitted in the sent command.`
+func (c *ShardedClient) LSet(ctx context.Context, listName string, elements []any) error {
+	return c.ShardFor(listName).LSet(ctx, listName, elements)
+}
+
+func (c *ShardedClient) Exec(packet *QueryPacket) ([]response.ResponseEntry, error) {
+	return nil, NewUsageError("*ShardedClient.Exec(): packets can't be routed, use ShardFor()", nil)
+}
+
+func (c *ShardedClient) ExecSingleActionPacketRaw(segments ...any) (response.ResponseEntry, error) {
+	return response.EmptyResponseEntry, NewUsageError("*ShardedClient.ExecSingleActionPacketRaw(): packets can't be routed, use ShardFor()", nil)
+}
+
+// https://docs.skytable.io/ddl/#use
+//
+// “USE KEYSPACE” and “USE TABLE” are unified into “USE”.
+func (c *ShardedClient) Use(ctx context.Context, path string) error {
+	return c.onAll(func(p *ConnPool) error {
+		return p.Use(ctx, path)
+	})
+}
+
+// https://docs.skytable.io/ddl/#inspect
+func (c *ShardedClient) InspectKeyspaces(ctx context.Context) (*protocol.TypedArray, error) {
+	return c.first().InspectKeyspaces(ctx)
+}
+
+// https://docs.skytable.io/ddl/#keyspaces
+func (c *ShardedClient) CreateKeyspace(ctx context.Context, name string) error {
+	return c.onAll(func(p *ConnPool) error {
+		return p.CreateKeyspace(ctx, name)
+	})
+}
+
+// https://docs.skytable.io/ddl/#keyspaces-1
+func (c *ShardedClient) DropKeyspace(ctx context.Context, name string) error {
+	return c.onAll(func(p *ConnPool) error {
+		return p.DropKeyspace(ctx, name)
+	})
+}
+
+// https://docs.skytable.io/ddl/#keyspaces-2
+//
+// If name is "", inspect the current keyspace
+func (c *ShardedClient) InspectKeyspace(ctx context.Context, name string) (*protocol.TypedArray, error) {
+	return c.first().InspectKeyspace(ctx, name)
+}
+
+// https://docs.skytable.io/ddl/#tables
+func (c *ShardedClient) CreateTable(ctx context.Context, path string, modelDesc any) error {
+	return c.onAll(func(p *ConnPool) error {
+		return p.CreateTable(ctx, path, modelDesc)
+	})
+}
+
+// https://docs.skytable.io/ddl/#tables-1
+func (c *ShardedClient) DropTable(ctx context.Context, path string) error {
+	return c.onAll(func(p *ConnPool) error {
+		return p.DropTable(ctx, path)
+	})
+}
+
+// https://docs.skytable.io/ddl/#tables-2
+//
+// If path is "", inspect the current table
+func (c *ShardedClient) InspectTable(ctx context.Context, path string) (protocol.ModelDescription, error) {
+	return c.first().InspectTable(ctx, path)
+}
+
+// https://docs.skytable.io/actions/sys#info
+func (c *ShardedClient) SysInfoVersion(ctx context.Context) (string, error) {
+	return c.first().SysInfoVersion(ctx)
+}
+
+// https://docs.skytable.io/actions/sys#info
+func (c *ShardedClient) SysInfoProtocol(ctx context.Context) (string, error) {
+	return c.first().SysInfoProtocol(ctx)
+}
+
+// https://docs.skytable.io/actions/sys#info
+func (c *ShardedClient) SysInfoProtoVer(ctx context.Context) (float32, error) {
+	return c.first().SysInfoProtoVer(ctx)
+}
+
+// https://docs.skytable.io/actions/sys#metric
+//
+// Returns true if "good", false when "critical"
+//
+// Only true if all the shards are "good".
+func (c *ShardedClient) SysMetricHealth(ctx context.Context) (bool, error) {
+	healths, err := onAllShards(c, func(p *ConnPool) (bool, error) {
+		return p.SysMetricHealth(ctx)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	for _, good := range healths {
+		if !good {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// https://docs.skytable.io/actions/sys#metric
+//
+// Sums the storage of all the shards.
+func (c *ShardedClient) SysMetricStorage(ctx context.Context) (uint64, error) {
+	sizes, err := onAllShards(c, func(p *ConnPool) (uint64, error) {
+		return p.SysMetricStorage(ctx)
+	})
+	return sum(sizes), err
+}
+
+// https://docs.skytable.io/actions/mksnap
+//
+// If name is "", it will only send "MKSNAP"
+func (c *ShardedClient) MKSnap(ctx context.Context, name string) error {
+	return c.onAll(func(p *ConnPool) error {
+		return p.MKSnap(ctx, name)
+	})
+}
+
+// https://docs.skytable.io/actions/whereami
+func (c *ShardedClient) WhereAmI(ctx context.Context) (string, error) {
+	return c.first().WhereAmI(ctx)
+}
+
+// https://docs.skytable.io/actions/dbsize
+//
+// If entity is "", check the current table
+//
+// Sums the sizes on all the shards.
+func (c *ShardedClient) DBSize(ctx context.Context, entity string) (uint64, error) {
+	sizes, err := onAllShards(c, func(p *ConnPool) (uint64, error) {
+		return p.DBSize(ctx, entity)
+	})
+	return sum(sizes), err
+}
+
+// https://docs.skytable.io/actions/keylen
+func (c *ShardedClient) KeyLen(ctx context.Context, key string) (uint64, error) {
+	return c.ShardFor(key).KeyLen(ctx, key)
+}
+
+// https://docs.skytable.io/actions/flushdb
+//
+// If entity is "", flush the current table
+func (c *ShardedClient) FlushDB(ctx context.Context, entity string) error {
+	return c.onAll(func(p *ConnPool) error {
+		return p.FlushDB(ctx, entity)
+	})
+}
+
+// https://docs.skytable.io/actions/lskeys
+//
+// Concatenates the keys of all the shards, truncated to the limit.
+func (c *ShardedClient) LSKeys(ctx context.Context, entity string, limit uint64) (*protocol.TypedArray, error) {
+	arrays, err := onAllShards(c, func(p *ConnPool) (*protocol.TypedArray, error) {
+		return p.LSKeys(ctx, entity, limit)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := &protocol.TypedArray{}
+	for _, a := range arrays {
+		if a == nil {
+			continue
+		}
+		keys.ArrayType = a.ArrayType
+		keys.ElementType = a.ElementType
+		keys.Elements = append(keys.Elements, a.Elements...)
+	}
+	if limit > 0 && uint64(len(keys.Elements)) > limit {
+		keys.Elements = keys.Elements[:limit]
+	}
+	return keys, nil
+}
@@ -0,0 +1,206 @@
+package skytable_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/action"
+	"github.com/No3371/go-skytable/protocol"
+	"github.com/No3371/go-skytable/skytabletest"
+)
+
+func newTestShards(t *testing.T, n int) []skytable.Shard {
+	var shards []skytable.Shard
+	for i := 0; i < n; i++ {
+		srv, err := skytabletest.NewServer(skytabletest.Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { srv.Close() })
+
+		shards = append(shards, skytable.Shard{
+			Name: fmt.Sprintf("shard-%d", i),
+			Pool: skytable.NewConnPool(srv.Addr(), skytable.DefaultConnPoolOptions),
+		})
+	}
+	return shards
+}
+
+func newTestShardedClient(t *testing.T, shards []skytable.Shard, opts skytable.ShardedOptions) *skytable.ShardedClient {
+	c, err := skytable.NewShardedClient(shards, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestNewShardedClient_Invalid(t *testing.T) {
+	var errUsage skytable.ErrInvalidUsage
+	if _, err := skytable.NewShardedClient(nil, skytable.ShardedOptions{}); !errors.As(err, &errUsage) {
+		t.Fatalf("expecting ErrInvalidUsage without shards but got %v", err)
+	}
+
+	shards := newTestShards(t, 2)
+	shards[1].Name = shards[0].Name
+	if _, err := skytable.NewShardedClient(shards, skytable.ShardedOptions{}); !errors.As(err, &errUsage) {
+		t.Fatalf("expecting ErrInvalidUsage with duplicate names but got %v", err)
+	}
+}
+
+func testKeys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+	}
+	return keys
+}
+
+func TestShardedClient_Routing(t *testing.T) {
+	ctx := context.Background()
+	var st skytable.Skytable
+	c := newTestShardedClient(t, newTestShards(t, 3), skytable.ShardedOptions{})
+	st = c
+
+	used := make(map[*skytable.ConnPool]bool)
+	for _, k := range testKeys(50) {
+		if err := st.Set(ctx, k, k); err != nil {
+			t.Fatal(err)
+		}
+
+		p := c.ShardFor(k)
+		used[p] = true
+		if v, err := p.GetBytes(ctx, k); err != nil || string(v) != k {
+			t.Fatalf("expecting %s on its shard but got %s (%v)", k, v, err)
+		}
+	}
+
+	if len(used) != 3 {
+		t.Fatalf("expecting the keys to be spread over 3 shards but got %d", len(used))
+	}
+
+	size, err := st.DBSize(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != 50 {
+		t.Fatalf("expecting DBSize to be summed to 50 but got %d", size)
+	}
+}
+
+func TestShardedClient_RingStability(t *testing.T) {
+	shards := newTestShards(t, 4)
+	before := newTestShardedClient(t, shards[:3], skytable.ShardedOptions{})
+	after := newTestShardedClient(t, shards, skytable.ShardedOptions{})
+
+	moved := 0
+	keys := testKeys(1000)
+	for _, k := range keys {
+		if before.ShardFor(k) != after.ShardFor(k) {
+			if after.ShardFor(k) != shards[3].Pool {
+				t.Fatalf("%s moved between existing shards", k)
+			}
+			moved++
+		}
+	}
+
+	if moved == 0 || moved > len(keys)/2 {
+		t.Fatalf("expecting about a quarter of the keys to move but %d/%d did", moved, len(keys))
+	}
+}
+
+func TestShardedClient_MultiKey(t *testing.T) {
+	ctx := context.Background()
+	c := newTestShardedClient(t, newTestShards(t, 3), skytable.ShardedOptions{})
+
+	keys := testKeys(20)
+	entries := make([]action.KVPair, len(keys))
+	for i, k := range keys {
+		entries[i] = action.KVPair{K: k, V: "v-" + k}
+	}
+
+	set, err := c.MSet(ctx, entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set != 20 {
+		t.Fatalf("expecting 20 keys set but got %d", set)
+	}
+
+	arr, err := c.MGet(ctx, append(keys, "missing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(arr.Elements) != 21 {
+		t.Fatalf("expecting 21 elements but got %d", len(arr.Elements))
+	}
+	for i, k := range keys {
+		if v, _ := arr.Elements[i].([]byte); string(v) != "v-"+k {
+			t.Fatalf("expecting v-%s at %d but got %v", k, i, arr.Elements[i])
+		}
+	}
+	if arr.Elements[20] != nil {
+		t.Fatalf("expecting nil for the missing key but got %v", arr.Elements[20])
+	}
+
+	existing, err := c.Exists(ctx, append(keys, "missing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if existing != 20 {
+		t.Fatalf("expecting 20 existing keys but got %d", existing)
+	}
+
+	deleted, err := c.Del(ctx, keys[:10])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 10 {
+		t.Fatalf("expecting 10 keys deleted but got %d", deleted)
+	}
+}
+
+func TestShardedClient_Strictness(t *testing.T) {
+	ctx := context.Background()
+	shards := newTestShards(t, 3)
+
+	keys := testKeys(10)
+	entries := make([]action.KVPair, len(keys))
+	for i, k := range keys {
+		entries[i] = action.KVPair{K: k, V: k}
+	}
+
+	single := newTestShardedClient(t, shards, skytable.ShardedOptions{Strictness: skytable.StrictSingleShard})
+	var errUsage skytable.ErrInvalidUsage
+	if err := single.SSet(ctx, entries); !errors.As(err, &errUsage) {
+		t.Fatalf("expecting ErrInvalidUsage but got %v", err)
+	}
+
+	checkAll := newTestShardedClient(t, shards, skytable.ShardedOptions{Strictness: skytable.StrictCheckAll})
+	if err := checkAll.Set(ctx, keys[9], "existing"); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkAll.SSet(ctx, entries); !errors.Is(err, protocol.ErrCodeOverwriteError) {
+		t.Fatalf("expecting ErrCodeOverwriteError but got %v", err)
+	}
+	existing, err := checkAll.Exists(ctx, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if existing != 1 {
+		t.Fatalf("expecting nothing to be set but %d keys exist", existing)
+	}
+
+	if err := checkAll.SDel(ctx, keys); !errors.Is(err, protocol.ErrCodeNil) {
+		t.Fatalf("expecting ErrCodeNil but got %v", err)
+	}
+
+	if err := checkAll.SSet(ctx, entries[:9]); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkAll.SDel(ctx, keys); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -3,6 +3,7 @@ package skytable_test
 import (
 	"context"
 	"errors"
+	"log"
 	"math/rand"
 	"net"
 	"os"
@@ -13,11 +14,43 @@ import (
 	"github.com/No3371/go-skytable"
 	"github.com/No3371/go-skytable/action"
 	"github.com/No3371/go-skytable/protocol"
+	"github.com/No3371/go-skytable/skytabletest"
 )
 
 const testUserName = "go-skytable-test"
 const NonAuthInstancePort = 2004
 
+// The addresses of the Auth-Enabled and Auth-Disabled instances.
+//
+// Unless GO_SKYTABLE_TEST_LIVE is set, TestMain replaces them with in-memory servers (see package skytabletest).
+var authAddr = &net.TCPAddr{IP: []byte{127, 0, 0, 1}, Port: int(protocol.DefaultPort)}
+var noAuthAddr = &net.TCPAddr{IP: []byte{127, 0, 0, 1}, Port: NonAuthInstancePort}
+
+func TestMain(m *testing.M) {
+	if _, live := os.LookupEnv("GO_SKYTABLE_TEST_LIVE"); live {
+		os.Exit(m.Run())
+	}
+
+	authSrv, err := skytabletest.NewServer(skytabletest.Options{Auth: true})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	noAuthSrv, err := skytabletest.NewServer(skytabletest.Options{})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	os.Setenv("GO_SKYTABLE_TEST_TOKEN", authSrv.AddUser(testUserName))
+	authAddr, noAuthAddr = authSrv.Addr(), noAuthSrv.Addr()
+
+	code := m.Run()
+
+	authSrv.Close()
+	noAuthSrv.Close()
+	os.Exit(code)
+}
+
 func GetTestToken() (string, bool) {
 	token, foundToken := os.LookupEnv("GO_SKYTABLE_TEST_TOKEN")
 	if foundToken {
@@ -40,7 +73,7 @@ func GetTestToken() (string, bool) {
 
 
 func NewConnNoAuth() (*skytable.Conn, error) {
-	return skytable.NewConn(&net.TCPAddr{IP: []byte{127, 0, 0, 1}, Port: NonAuthInstancePort})
+	return skytable.NewConn(noAuthAddr)
 }
 
 func NewConnAuth() (*skytable.Conn, error) {
@@ -55,18 +88,16 @@ func NewConnAuth() (*skytable.Conn, error) {
 		return u, t, nil
 	}
 
-	return skytable.NewConnAuth(&net.TCPAddr{IP: []byte{127, 0, 0, 1}, Port: int(protocol.DefaultPort)}, auth)
+	return skytable.NewConnAuth(authAddr, auth)
 }
 
 func NewConnPoolNoAuth() (*skytable.ConnPool, error) {
-	localAddr := &net.TCPAddr{IP: []byte{127, 0, 0, 1}, Port: NonAuthInstancePort}
-	c := skytable.NewConnPool(localAddr, skytable.DefaultConnPoolOptions)
+	c := skytable.NewConnPool(noAuthAddr, skytable.DefaultConnPoolOptions)
 
 	return c, nil
 }
 
 func NewConnPoolAuth() (*skytable.ConnPool, error) {
-	localAddr := &net.TCPAddr{IP: []byte{127, 0, 0, 1}, Port: int(protocol.DefaultPort)}
 	authProvider:= func() (username, token string, err error) {
 		t, gotToken := GetTestToken()
 		if !gotToken {
@@ -74,7 +105,7 @@ func NewConnPoolAuth() (*skytable.ConnPool, error) {
 		}
 		return testUserName, t, nil
 	}
-	c := skytable.NewConnPool(localAddr, skytable.ConnPoolOptions{
+	c := skytable.NewConnPool(authAddr, skytable.ConnPoolOptions{
 		AuthProvider: authProvider,
 	})
 
@@ -96,6 +127,46 @@ func TestConnPoolLocalAuth(t *testing.T) {
 	}
 }
 
+func TestConnPoolDoEachConn(t *testing.T) {
+	cp := skytable.NewConnPool(noAuthAddr, skytable.ConnPoolOptions{Cap: 2})
+
+	_, push1, err := cp.RentConn(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, push2, err := cp.RentConn(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	push1()
+	push2()
+
+	for i := 0; i < 2; i++ {
+		n := 0
+		err = cp.DoEachConn(func(conn *skytable.Conn) error {
+			n++
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 2 {
+			t.Fatalf("expecting DoEachConn to visit 2 conns but got %d", n)
+		}
+	}
+
+	// Every conn is back in the pool
+	for i := 0; i < 2; i++ {
+		_, _, err := cp.RentConn(true)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if cp.OpenedConns() != 2 {
+		t.Fatalf("expecting 2 opened conns but got %d", cp.OpenedConns())
+	}
+}
+
 func TestConnPoolLocalSetGetBurst(t *testing.T) {
 	bursts := []int{144, 256, 1024}
 
@@ -182,7 +253,7 @@ func TestConnPoolLocalSetGetBurst(t *testing.T) {
 }
 
 func TestConnLocalNoAuth(t *testing.T) {
-	_, err := skytable.NewConn(&net.TCPAddr{IP: []byte{127, 0, 0, 1}, Port: NonAuthInstancePort})
+	_, err := skytable.NewConn(noAuthAddr)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -208,7 +279,7 @@ func TestConnLocalAuthFail(t *testing.T) {
 		return "a", "_b_", nil
 	}
 
-	_, err := skytable.NewConnAuth(&net.TCPAddr{IP: []byte{127, 0, 0, 1}, Port: int(protocol.DefaultPort)}, auth)
+	_, err := skytable.NewConnAuth(authAddr, auth)
 	if err == nil {
 		t.Fatal(err)
 	}
@@ -302,8 +373,8 @@ func TestConnLocalGetNULL(t *testing.T) {
 	}
 
 	resp, err := c.Get(ctx, "key-not-to-be-found-you-really-should-not-have-this-key")
-	if err != nil {
-		t.Fatal(err)
+	if err != protocol.ErrCodeNil {
+		t.Fatalf("expecting nil err but got %v", err)
 	}
 
 	t.Log(resp)
@@ -744,6 +815,54 @@ func TestAutoReconnect(t *testing.T) {
 	}
 }
 
+func TestAutoReconnectFaults(t *testing.T) {
+	ctx := context.Background()
+
+	p, err := skytabletest.NewProxy(noAuthAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	faults := []struct {
+		name   string
+		inject func()
+	}{
+		{"DropNextRequest", p.DropNextRequest},
+		{"InjectPacketError", p.InjectPacketError},
+		{"TruncateNextResponse", func() { p.TruncateNextResponse(3) }},
+		{"CloseConns", p.CloseConns},
+	}
+
+	for _, f := range faults {
+		t.Run(f.name, func(t *testing.T) {
+			c, err := skytable.NewConn(p.Addr())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f.inject()
+			err = c.Heya(ctx, "")
+			if err == nil {
+				t.Fatal("Heya: expecting error but got nil")
+			}
+			t.Logf("err: %s", err)
+
+			var errUsage skytable.ErrInvalidUsage
+			err = c.Heya(ctx, "")
+			if !errors.As(err, &errUsage) {
+				t.Fatalf("expecting ErrInvalidUsage but got %v", err)
+			}
+
+			c.EnableAutoReconnect()
+			err = c.Heya(ctx, "")
+			if err != nil {
+				t.Fatalf("expecting reconnected but got %s", err)
+			}
+		})
+	}
+}
+
 func TestInterface(t *testing.T) {
 	var st skytable.Skytable
 
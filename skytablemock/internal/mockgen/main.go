@@ -0,0 +1,262 @@
+// mockgen generates the methods of skytablemock.Mock from the Skytable interface.
+//
+// Usage (from the skytablemock directory, see the go:generate directive in mock.go):
+//
+//	go run ./internal/mockgen -src ../skytable.go -out skytable_mock.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	interfaceName = "Skytable"
+	rootImport    = "github.com/No3371/go-skytable"
+)
+
+func main() {
+	src := flag.String("src", "../skytable.go", "the file declaring the Skytable interface")
+	out := flag.String("out", "skytable_mock.go", "the generated file")
+	flag.Parse()
+
+	generated, err := generate(*src)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(*out, generated, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type method struct {
+	name    string
+	params  []string // "name type"
+	args    []string // names passed to call()
+	results []string // types
+}
+
+func generate(src string) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, src, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	imports := make(map[string]string)
+	for _, spec := range f.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = path
+	}
+
+	iface := findInterface(f)
+	if iface == nil {
+		return nil, fmt.Errorf("interface %s not found in %s", interfaceName, src)
+	}
+
+	used := map[string]string{"skytable": rootImport}
+	var methods []method
+	for _, field := range iface.Methods.List {
+		ft, ok := field.Type.(*ast.FuncType)
+		if !ok {
+			return nil, fmt.Errorf("embedded interfaces are not supported")
+		}
+
+		for _, name := range field.Names {
+			m := method{name: name.Name}
+
+			i := 0
+			for _, p := range ft.Params.List {
+				names := len(p.Names)
+				if names == 0 {
+					names = 1
+				}
+				for n := 0; n < names; n++ {
+					arg := fmt.Sprintf("a%d", i)
+					if len(p.Names) > 0 && p.Names[n].Name != "m" && p.Names[n].Name != "_" {
+						arg = p.Names[n].Name
+					}
+					i++
+					typ, err := typeString(fset, p.Type, imports, used)
+					if err != nil {
+						return nil, err
+					}
+					m.params = append(m.params, arg+" "+typ)
+					m.args = append(m.args, arg)
+				}
+			}
+
+			if ft.Results != nil {
+				for _, r := range ft.Results.List {
+					names := len(r.Names)
+					if names == 0 {
+						names = 1
+					}
+					typ, err := typeString(fset, r.Type, imports, used)
+					if err != nil {
+						return nil, err
+					}
+					for n := 0; n < names; n++ {
+						m.results = append(m.results, typ)
+					}
+				}
+			}
+
+			if len(m.results) == 0 || m.results[len(m.results)-1] != "error" {
+				return nil, fmt.Errorf("%s: the last result must be an error", m.name)
+			}
+
+			methods = append(methods, m)
+		}
+	}
+
+	return render(methods, used)
+}
+
+func findInterface(f *ast.File) *ast.InterfaceType {
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if ts.Name.Name != interfaceName {
+				continue
+			}
+			if it, ok := ts.Type.(*ast.InterfaceType); ok {
+				return it
+			}
+		}
+	}
+	return nil
+}
+
+// typeString prints the type expression as seen from outside of package skytable,
+// collecting the imports it needs.
+func typeString(fset *token.FileSet, expr ast.Expr, imports, used map[string]string) (string, error) {
+	var err error
+	expr = qualify(expr, func(pkg string) {
+		path, found := imports[pkg]
+		if !found {
+			err = fmt.Errorf("unknown package: %s", pkg)
+			return
+		}
+		used[pkg] = path
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, expr); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// qualify returns a copy of the type expression whose identifiers declared in package skytable are qualified.
+func qualify(expr ast.Expr, usePkg func(pkg string)) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if isPredeclared(e.Name) {
+			return e
+		}
+		return &ast.SelectorExpr{X: ast.NewIdent("skytable"), Sel: ast.NewIdent(e.Name)}
+	case *ast.SelectorExpr:
+		usePkg(e.X.(*ast.Ident).Name)
+		return e
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: qualify(e.X, usePkg)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: e.Len, Elt: qualify(e.Elt, usePkg)}
+	case *ast.Ellipsis:
+		return &ast.Ellipsis{Elt: qualify(e.Elt, usePkg)}
+	case *ast.MapType:
+		return &ast.MapType{Key: qualify(e.Key, usePkg), Value: qualify(e.Value, usePkg)}
+	default:
+		return e
+	}
+}
+
+func isPredeclared(name string) bool {
+	switch name {
+	case "any", "bool", "byte", "error", "rune", "string",
+		"int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"float32", "float64", "complex64", "complex128":
+		return true
+	}
+	return false
+}
+
+func render(methods []method, used map[string]string) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by skytablemock/internal/mockgen; DO NOT EDIT.\n\n")
+	b.WriteString("package skytablemock\n\n")
+
+	paths := make([]string, 0, len(used))
+	for _, p := range used {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	b.WriteString("import (\n")
+	for i, p := range paths {
+		if i > 0 && !strings.Contains(paths[i-1], ".") && strings.Contains(p, ".") {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "\t%q\n", p)
+	}
+	b.WriteString(")\n\n")
+
+	b.WriteString("var _ skytable.Skytable = (*Mock)(nil)\n\n")
+
+	b.WriteString("// resultCounts maps the methods of skytable.Skytable to the number of their results.\n")
+	b.WriteString("var resultCounts = map[string]int{\n")
+	for _, m := range methods {
+		fmt.Fprintf(&b, "\t%q: %d,\n", m.name, len(m.results))
+	}
+	b.WriteString("}\n")
+
+	for _, m := range methods {
+		fmt.Fprintf(&b, "\n// %s implements skytable.Skytable.\n", m.name)
+		fmt.Fprintf(&b, "func (m *Mock) %s(%s) ", m.name, strings.Join(m.params, ", "))
+		if len(m.results) == 1 {
+			b.WriteString(m.results[0])
+		} else {
+			fmt.Fprintf(&b, "(%s)", strings.Join(m.results, ", "))
+		}
+		b.WriteString(" {\n")
+
+		results := make([]string, len(m.results))
+		for i, r := range m.results {
+			fmt.Fprintf(&b, "\tvar r%d %s\n", i, r)
+			results[i] = fmt.Sprintf("&r%d", i)
+		}
+		fmt.Fprintf(&b, "\tm.call(%q, []any{%s}, %s)\n", m.name, strings.Join(m.args, ", "), strings.Join(results, ", "))
+
+		for i := range results {
+			results[i] = results[i][1:]
+		}
+		fmt.Fprintf(&b, "\treturn %s\n", strings.Join(results, ", "))
+		b.WriteString("}\n")
+	}
+
+	return format.Source(b.Bytes())
+}
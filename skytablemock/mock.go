@@ -0,0 +1,259 @@
+// Package skytablemock provides a programmable mock of skytable.Skytable,
+// so code depending on the interface can be unit-tested without a server.
+//
+// The methods of Mock are generated from the interface, run `go generate ./skytablemock` after changing it.
+//
+//	m := skytablemock.New(t)
+//	m.On("GetString", skytablemock.Any, "user:1").Return("alice", nil)
+//	m.On("Set", skytablemock.Any, skytablemock.Any, skytablemock.Any).Fail(protocol.ErrCodeOverwriteError).Once()
+//
+//	svc := NewService(m) // accepts skytable.Skytable
+//	...
+//	m.AssertExpectations(t)
+package skytablemock
+
+//go:generate go run ./internal/mockgen -src ../skytable.go -out skytable_mock.go
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ErrUnexpectedCall is returned by calls matching no expectation.
+var ErrUnexpectedCall = errors.New("skytablemock: unexpected call")
+
+// TestingT is the subset of testing.TB used by Mock.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// Any matches any argument.
+var Any = Matcher(func(arg any) bool { return true })
+
+// Matcher matches an argument by a predicate, instead of by equality.
+type Matcher func(arg any) bool
+
+// Call is a recorded call to Mock.
+type Call struct {
+	Method string
+	Args   []any
+}
+
+// Expectation is a programmed behaviour of a method, created by Mock.On().
+type Expectation struct {
+	method  string
+	args    []any
+	returns []any
+	err     error
+	run     func(args []any)
+	times   int // 0 means unlimited
+	called  int
+}
+
+// Return sets the values to be returned, one per result of the method.
+//
+// Numbers are converted to the numeric result type (e.g. an untyped constant 3 to uint64),
+// nil is the zero value. Canned response.ResponseEntry values are returned as is.
+func (e *Expectation) Return(values ...any) *Expectation {
+	if len(values) != resultCounts[e.method] {
+		panic(fmt.Sprintf("skytablemock: %s returns %d values but got %d", e.method, resultCounts[e.method], len(values)))
+	}
+	e.returns = values
+	return e
+}
+
+// Fail makes the method return zero values and err.
+func (e *Expectation) Fail(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+// Run sets a function to be called with the arguments, before returning.
+func (e *Expectation) Run(fn func(args []any)) *Expectation {
+	e.run = fn
+	return e
+}
+
+// Times limits the expectation to match n calls, after which it's skipped and the next one is tried.
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+	return e
+}
+
+// Once is Times(1).
+func (e *Expectation) Once() *Expectation {
+	return e.Times(1)
+}
+
+func (e *Expectation) matches(args []any) bool {
+	if e.times > 0 && e.called >= e.times {
+		return false
+	}
+
+	for i, expected := range e.args {
+		if i >= len(args) {
+			return false
+		}
+		if m, ok := expected.(Matcher); ok {
+			if !m(args[i]) {
+				return false
+			}
+		} else if !reflect.DeepEqual(expected, args[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Mock implements skytable.Skytable.
+//
+// Calls are matched against the expectations of the method in the order they were added.
+// A call matching no expectation fails the test (if a TestingT is provided) and returns ErrUnexpectedCall.
+type Mock struct {
+	t TestingT
+
+	mu           sync.Mutex
+	expectations map[string][]*Expectation
+	calls        []Call
+}
+
+// New returns a Mock reporting unexpected calls to t, which can be nil.
+func New(t TestingT) *Mock {
+	return &Mock{
+		t:            t,
+		expectations: make(map[string][]*Expectation),
+	}
+}
+
+// On adds an expectation of the method called with the arguments.
+//
+// Each argument is matched by equality (reflect.DeepEqual) or by a Matcher, trailing arguments can be omitted to match anything.
+// The expectation returns zero values until Return() or Fail() is called.
+func (m *Mock) On(method string, args ...any) *Expectation {
+	if _, found := resultCounts[method]; !found {
+		panic(fmt.Sprintf("skytablemock: %s is not a method of skytable.Skytable", method))
+	}
+
+	e := &Expectation{method: method, args: args}
+
+	m.mu.Lock()
+	m.expectations[method] = append(m.expectations[method], e)
+	m.mu.Unlock()
+
+	return e
+}
+
+// Calls returns all the recorded calls in order.
+func (m *Mock) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Call(nil), m.calls...)
+}
+
+// CallsTo returns the recorded calls to the method in order.
+func (m *Mock) CallsTo(method string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var calls []Call
+	for _, c := range m.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// AssertExpectations fails the test if an expectation was never matched, or matched fewer times than set by Times().
+func (m *Mock) AssertExpectations(t TestingT) bool {
+	t.Helper()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	met := true
+	for method, es := range m.expectations {
+		for _, e := range es {
+			if e.times > 0 && e.called < e.times {
+				t.Errorf("skytablemock: expected %s%v to be called %d times but got %d", method, e.args, e.times, e.called)
+				met = false
+			} else if e.times == 0 && e.called == 0 {
+				t.Errorf("skytablemock: expected %s%v to be called", method, e.args)
+				met = false
+			}
+		}
+	}
+	return met
+}
+
+// Reset removes all the expectations and the recorded calls.
+func (m *Mock) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expectations = make(map[string][]*Expectation)
+	m.calls = nil
+}
+
+// call records the call and writes the results of the matched expectation to the pointers.
+// The last result of every method is an error.
+func (m *Mock) call(method string, args []any, results ...any) {
+	m.mu.Lock()
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+
+	var matched *Expectation
+	for _, e := range m.expectations[method] {
+		if e.matches(args) {
+			e.called++
+			matched = e
+			break
+		}
+	}
+	m.mu.Unlock()
+
+	errPtr := results[len(results)-1].(*error)
+
+	if matched == nil {
+		if m.t != nil {
+			m.t.Helper()
+			m.t.Errorf("skytablemock: unexpected call: %s%v", method, args)
+		}
+		*errPtr = ErrUnexpectedCall
+		return
+	}
+
+	if matched.run != nil {
+		matched.run(args)
+	}
+
+	if matched.err != nil {
+		*errPtr = matched.err
+		return
+	}
+
+	for i, v := range matched.returns {
+		if v == nil {
+			continue
+		}
+
+		dst := reflect.ValueOf(results[i]).Elem()
+		src := reflect.ValueOf(v)
+		switch {
+		case src.Type().AssignableTo(dst.Type()):
+			dst.Set(src)
+		case isNumeric(src.Kind()) && isNumeric(dst.Kind()):
+			dst.Set(src.Convert(dst.Type()))
+		default:
+			panic(fmt.Sprintf("skytablemock: %s: return value %d: %T is not assignable to %s", method, i, v, dst.Type()))
+		}
+	}
+}
+
+func isNumeric(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
@@ -0,0 +1,122 @@
+package skytablemock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/protocol"
+	"github.com/No3371/go-skytable/response"
+)
+
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestMock_InSyncWithInterface(t *testing.T) {
+	it := reflect.TypeOf((*skytable.Skytable)(nil)).Elem()
+	if it.NumMethod() != len(resultCounts) {
+		t.Fatalf("skytable.Skytable has %d methods but the mock has %d, run go generate", it.NumMethod(), len(resultCounts))
+	}
+
+	for i := 0; i < it.NumMethod(); i++ {
+		m := it.Method(i)
+		if resultCounts[m.Name] != m.Type.NumOut() {
+			t.Fatalf("%s: out of sync, run go generate", m.Name)
+		}
+	}
+}
+
+func TestMock_Return(t *testing.T) {
+	ctx := context.Background()
+	m := New(t)
+
+	entry := response.ResponseEntry{DataType: protocol.DataTypeString, Value: "v"}
+	m.On("Get", Any, "k").Return(entry, nil)
+	m.On("Exists", Any, []string{"a", "b"}).Return(2, nil)
+
+	var db skytable.Skytable = m
+
+	got, err := db.Get(ctx, "k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != entry {
+		t.Fatalf("expecting %v but got %v", entry, got)
+	}
+
+	existing, err := db.Exists(ctx, []string{"a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if existing != 2 {
+		t.Fatalf("expecting 2 but got %d", existing)
+	}
+
+	m.AssertExpectations(t)
+}
+
+func TestMock_FailOnce(t *testing.T) {
+	ctx := context.Background()
+	m := New(t)
+
+	m.On("Set", Any, "k").Fail(protocol.ErrCodeServerError).Once()
+	m.On("Set", Any, "k").Return(nil)
+
+	if err := m.Set(ctx, "k", "v"); err != protocol.ErrCodeServerError {
+		t.Fatalf("expecting injected error but got %v", err)
+	}
+	if err := m.Set(ctx, "k", "v"); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := m.CallsTo("Set")
+	if len(calls) != 2 || calls[1].Args[2] != "v" {
+		t.Fatalf("unexpected calls: %v", calls)
+	}
+
+	m.AssertExpectations(t)
+}
+
+func TestMock_Unexpected(t *testing.T) {
+	r := &recorder{}
+	m := New(r)
+
+	m.On("Del", Any, Matcher(func(arg any) bool { return len(arg.([]string)) == 1 })).Return(1, nil)
+
+	if _, err := m.Del(context.Background(), []string{"a", "b"}); !errors.Is(err, ErrUnexpectedCall) {
+		t.Fatalf("expecting ErrUnexpectedCall but got %v", err)
+	}
+	if len(r.errors) != 1 {
+		t.Fatalf("expecting the unexpected call to be reported: %v", r.errors)
+	}
+
+	if m.AssertExpectations(r) {
+		t.Fatal("expecting unmet expectation")
+	}
+}
+
+func TestMock_Run(t *testing.T) {
+	m := New(t)
+
+	var pushed []any
+	m.On("LModPush").Run(func(args []any) {
+		pushed = append(pushed, args[2].([]any)...)
+	}).Return(nil)
+
+	if err := m.LModPush(context.Background(), "l", []any{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(pushed) != 2 {
+		t.Fatalf("expecting 2 elements but got %v", pushed)
+	}
+}
@@ -0,0 +1,546 @@
+// Code generated by skytablemock/internal/mockgen; DO NOT EDIT.
+
+package skytablemock
+
+import (
+	"context"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/action"
+	"github.com/No3371/go-skytable/protocol"
+	"github.com/No3371/go-skytable/response"
+)
+
+var _ skytable.Skytable = (*Mock)(nil)
+
+// resultCounts maps the methods of skytable.Skytable to the number of their results.
+var resultCounts = map[string]int{
+	"Heya":                      1,
+	"AuthLogin":                 1,
+	"AuthLogout":                1,
+	"AuthClaim":                 2,
+	"AuthAddUser":               2,
+	"AuthDelUser":               1,
+	"AuthRestore":               2,
+	"AuthListUser":              2,
+	"AuthWhoAmI":                2,
+	"Exists":                    2,
+	"Del":                       2,
+	"SDel":                      1,
+	"Get":                       2,
+	"GetString":                 2,
+	"GetBytes":                  2,
+	"MGet":                      2,
+	"Pop":                       2,
+	"MPop":                      2,
+	"Set":                       1,
+	"MSetB":                     2,
+	"MSet":                      2,
+	"SSet":                      1,
+	"USet":                      2,
+	"Update":                    1,
+	"MUpdate":                   2,
+	"SUpdate":                   1,
+	"LGet":                      2,
+	"LGetLimit":                 2,
+	"LGetLen":                   2,
+	"LGetValueAt":               2,
+	"LGetFirst":                 2,
+	"LGetLast":                  2,
+	"LGetRange":                 2,
+	"LModPush":                  1,
+	"LModInsert":                1,
+	"LModPop":                   2,
+	"LModPopIndex":              2,
+	"LModRemove":                1,
+	"LModClear":                 1,
+	"LSet":                      1,
+	"Exec":                      2,
+	"ExecSingleActionPacketRaw": 2,
+	"Use":                       1,
+	"InspectKeyspaces":          2,
+	"CreateKeyspace":            1,
+	"DropKeyspace":              1,
+	"InspectKeyspace":           2,
+	"CreateTable":               1,
+	"DropTable":                 1,
+	"InspectTable":              2,
+	"SysInfoVersion":            2,
+	"SysInfoProtocol":           2,
+	"SysInfoProtoVer":           2,
+	"SysMetricHealth":           2,
+	"SysMetricStorage":          2,
+	"MKSnap":                    1,
+	"WhereAmI":                  2,
+	"DBSize":                    2,
+	"KeyLen":                    2,
+	"FlushDB":                   1,
+	"LSKeys":                    2,
+}
+
+// Heya implements skytable.Skytable.
+func (m *Mock) Heya(ctx context.Context, echo string) error {
+	var r0 error
+	m.call("Heya", []any{ctx, echo}, &r0)
+	return r0
+}
+
+// AuthLogin implements skytable.Skytable.
+func (m *Mock) AuthLogin(ctx context.Context, authProvider skytable.AuthProvider) error {
+	var r0 error
+	m.call("AuthLogin", []any{ctx, authProvider}, &r0)
+	return r0
+}
+
+// AuthLogout implements skytable.Skytable.
+func (m *Mock) AuthLogout(ctx context.Context) error {
+	var r0 error
+	m.call("AuthLogout", []any{ctx}, &r0)
+	return r0
+}
+
+// AuthClaim implements skytable.Skytable.
+func (m *Mock) AuthClaim(ctx context.Context, originKey string) (string, error) {
+	var r0 string
+	var r1 error
+	m.call("AuthClaim", []any{ctx, originKey}, &r0, &r1)
+	return r0, r1
+}
+
+// AuthAddUser implements skytable.Skytable.
+func (m *Mock) AuthAddUser(ctx context.Context, username string) (string, error) {
+	var r0 string
+	var r1 error
+	m.call("AuthAddUser", []any{ctx, username}, &r0, &r1)
+	return r0, r1
+}
+
+// AuthDelUser implements skytable.Skytable.
+func (m *Mock) AuthDelUser(ctx context.Context, username string) error {
+	var r0 error
+	m.call("AuthDelUser", []any{ctx, username}, &r0)
+	return r0
+}
+
+// AuthRestore implements skytable.Skytable.
+func (m *Mock) AuthRestore(ctx context.Context, originKey string, username string) (string, error) {
+	var r0 string
+	var r1 error
+	m.call("AuthRestore", []any{ctx, originKey, username}, &r0, &r1)
+	return r0, r1
+}
+
+// AuthListUser implements skytable.Skytable.
+func (m *Mock) AuthListUser(ctx context.Context) (*protocol.TypedArray, error) {
+	var r0 *protocol.TypedArray
+	var r1 error
+	m.call("AuthListUser", []any{ctx}, &r0, &r1)
+	return r0, r1
+}
+
+// AuthWhoAmI implements skytable.Skytable.
+func (m *Mock) AuthWhoAmI(ctx context.Context) (string, error) {
+	var r0 string
+	var r1 error
+	m.call("AuthWhoAmI", []any{ctx}, &r0, &r1)
+	return r0, r1
+}
+
+// Exists implements skytable.Skytable.
+func (m *Mock) Exists(ctx context.Context, keys []string) (uint64, error) {
+	var r0 uint64
+	var r1 error
+	m.call("Exists", []any{ctx, keys}, &r0, &r1)
+	return r0, r1
+}
+
+// Del implements skytable.Skytable.
+func (m *Mock) Del(ctx context.Context, keys []string) (uint64, error) {
+	var r0 uint64
+	var r1 error
+	m.call("Del", []any{ctx, keys}, &r0, &r1)
+	return r0, r1
+}
+
+// SDel implements skytable.Skytable.
+func (m *Mock) SDel(ctx context.Context, keys []string) error {
+	var r0 error
+	m.call("SDel", []any{ctx, keys}, &r0)
+	return r0
+}
+
+// Get implements skytable.Skytable.
+func (m *Mock) Get(ctx context.Context, key string) (response.ResponseEntry, error) {
+	var r0 response.ResponseEntry
+	var r1 error
+	m.call("Get", []any{ctx, key}, &r0, &r1)
+	return r0, r1
+}
+
+// GetString implements skytable.Skytable.
+func (m *Mock) GetString(ctx context.Context, key string) (string, error) {
+	var r0 string
+	var r1 error
+	m.call("GetString", []any{ctx, key}, &r0, &r1)
+	return r0, r1
+}
+
+// GetBytes implements skytable.Skytable.
+func (m *Mock) GetBytes(ctx context.Context, key string) ([]byte, error) {
+	var r0 []byte
+	var r1 error
+	m.call("GetBytes", []any{ctx, key}, &r0, &r1)
+	return r0, r1
+}
+
+// MGet implements skytable.Skytable.
+func (m *Mock) MGet(ctx context.Context, keys []string) (*protocol.TypedArray, error) {
+	var r0 *protocol.TypedArray
+	var r1 error
+	m.call("MGet", []any{ctx, keys}, &r0, &r1)
+	return r0, r1
+}
+
+// Pop implements skytable.Skytable.
+func (m *Mock) Pop(ctx context.Context, key string) (response.ResponseEntry, error) {
+	var r0 response.ResponseEntry
+	var r1 error
+	m.call("Pop", []any{ctx, key}, &r0, &r1)
+	return r0, r1
+}
+
+// MPop implements skytable.Skytable.
+func (m *Mock) MPop(ctx context.Context, keys []string) (*protocol.TypedArray, error) {
+	var r0 *protocol.TypedArray
+	var r1 error
+	m.call("MPop", []any{ctx, keys}, &r0, &r1)
+	return r0, r1
+}
+
+// Set implements skytable.Skytable.
+func (m *Mock) Set(ctx context.Context, key string, value any) error {
+	var r0 error
+	m.call("Set", []any{ctx, key, value}, &r0)
+	return r0
+}
+
+// MSetB implements skytable.Skytable.
+func (m *Mock) MSetB(ctx context.Context, keys []string, values []any) (uint64, error) {
+	var r0 uint64
+	var r1 error
+	m.call("MSetB", []any{ctx, keys, values}, &r0, &r1)
+	return r0, r1
+}
+
+// MSet implements skytable.Skytable.
+func (m *Mock) MSet(ctx context.Context, entries []action.KVPair) (uint64, error) {
+	var r0 uint64
+	var r1 error
+	m.call("MSet", []any{ctx, entries}, &r0, &r1)
+	return r0, r1
+}
+
+// SSet implements skytable.Skytable.
+func (m *Mock) SSet(ctx context.Context, entries []action.KVPair) error {
+	var r0 error
+	m.call("SSet", []any{ctx, entries}, &r0)
+	return r0
+}
+
+// USet implements skytable.Skytable.
+func (m *Mock) USet(ctx context.Context, entries ...action.KVPair) (uint64, error) {
+	var r0 uint64
+	var r1 error
+	m.call("USet", []any{ctx, entries}, &r0, &r1)
+	return r0, r1
+}
+
+// Update implements skytable.Skytable.
+func (m *Mock) Update(ctx context.Context, key string, value any) error {
+	var r0 error
+	m.call("Update", []any{ctx, key, value}, &r0)
+	return r0
+}
+
+// MUpdate implements skytable.Skytable.
+func (m *Mock) MUpdate(ctx context.Context, entries []action.KVPair) (uint64, error) {
+	var r0 uint64
+	var r1 error
+	m.call("MUpdate", []any{ctx, entries}, &r0, &r1)
+	return r0, r1
+}
+
+// SUpdate implements skytable.Skytable.
+func (m *Mock) SUpdate(ctx context.Context, entries []action.KVPair) error {
+	var r0 error
+	m.call("SUpdate", []any{ctx, entries}, &r0)
+	return r0
+}
+
+// LGet implements skytable.Skytable.
+func (m *Mock) LGet(ctx context.Context, listName string) (*protocol.TypedArray, error) {
+	var r0 *protocol.TypedArray
+	var r1 error
+	m.call("LGet", []any{ctx, listName}, &r0, &r1)
+	return r0, r1
+}
+
+// LGetLimit implements skytable.Skytable.
+func (m *Mock) LGetLimit(ctx context.Context, listName string, limit uint64) (*protocol.TypedArray, error) {
+	var r0 *protocol.TypedArray
+	var r1 error
+	m.call("LGetLimit", []any{ctx, listName, limit}, &r0, &r1)
+	return r0, r1
+}
+
+// LGetLen implements skytable.Skytable.
+func (m *Mock) LGetLen(ctx context.Context, listName string) (uint64, error) {
+	var r0 uint64
+	var r1 error
+	m.call("LGetLen", []any{ctx, listName}, &r0, &r1)
+	return r0, r1
+}
+
+// LGetValueAt implements skytable.Skytable.
+func (m *Mock) LGetValueAt(ctx context.Context, listName string, index uint64) (response.ResponseEntry, error) {
+	var r0 response.ResponseEntry
+	var r1 error
+	m.call("LGetValueAt", []any{ctx, listName, index}, &r0, &r1)
+	return r0, r1
+}
+
+// LGetFirst implements skytable.Skytable.
+func (m *Mock) LGetFirst(ctx context.Context, listName string) (response.ResponseEntry, error) {
+	var r0 response.ResponseEntry
+	var r1 error
+	m.call("LGetFirst", []any{ctx, listName}, &r0, &r1)
+	return r0, r1
+}
+
+// LGetLast implements skytable.Skytable.
+func (m *Mock) LGetLast(ctx context.Context, listName string) (response.ResponseEntry, error) {
+	var r0 response.ResponseEntry
+	var r1 error
+	m.call("LGetLast", []any{ctx, listName}, &r0, &r1)
+	return r0, r1
+}
+
+// LGetRange implements skytable.Skytable.
+func (m *Mock) LGetRange(ctx context.Context, listName string, from uint64, to uint64) (*protocol.TypedArray, error) {
+	var r0 *protocol.TypedArray
+	var r1 error
+	m.call("LGetRange", []any{ctx, listName, from, to}, &r0, &r1)
+	return r0, r1
+}
+
+// LModPush implements skytable.Skytable.
+func (m *Mock) LModPush(ctx context.Context, listName string, elements []any) error {
+	var r0 error
+	m.call("LModPush", []any{ctx, listName, elements}, &r0)
+	return r0
+}
+
+// LModInsert implements skytable.Skytable.
+func (m *Mock) LModInsert(ctx context.Context, listName string, index uint64, element any) error {
+	var r0 error
+	m.call("LModInsert", []any{ctx, listName, index, element}, &r0)
+	return r0
+}
+
+// LModPop implements skytable.Skytable.
+func (m *Mock) LModPop(ctx context.Context, listName string) (response.ResponseEntry, error) {
+	var r0 response.ResponseEntry
+	var r1 error
+	m.call("LModPop", []any{ctx, listName}, &r0, &r1)
+	return r0, r1
+}
+
+// LModPopIndex implements skytable.Skytable.
+func (m *Mock) LModPopIndex(ctx context.Context, listName string, index uint64) (response.ResponseEntry, error) {
+	var r0 response.ResponseEntry
+	var r1 error
+	m.call("LModPopIndex", []any{ctx, listName, index}, &r0, &r1)
+	return r0, r1
+}
+
+// LModRemove implements skytable.Skytable.
+func (m *Mock) LModRemove(ctx context.Context, listName string, index uint64) error {
+	var r0 error
+	m.call("LModRemove", []any{ctx, listName, index}, &r0)
+	return r0
+}
+
+// LModClear implements skytable.Skytable.
+func (m *Mock) LModClear(ctx context.Context, listName string) error {
+	var r0 error
+	m.call("LModClear", []any{ctx, listName}, &r0)
+	return r0
+}
+
+// LSet implements skytable.Skytable.
+func (m *Mock) LSet(ctx context.Context, listName string, elements []any) error {
+	var r0 error
+	m.call("LSet", []any{ctx, listName, elements}, &r0)
+	return r0
+}
+
+// Exec implements skytable.Skytable.
+func (m *Mock) Exec(packet *skytable.QueryPacket) ([]response.ResponseEntry, error) {
+	var r0 []response.ResponseEntry
+	var r1 error
+	m.call("Exec", []any{packet}, &r0, &r1)
+	return r0, r1
+}
+
+// ExecSingleActionPacketRaw implements skytable.Skytable.
+func (m *Mock) ExecSingleActionPacketRaw(segments ...any) (response.ResponseEntry, error) {
+	var r0 response.ResponseEntry
+	var r1 error
+	m.call("ExecSingleActionPacketRaw", []any{segments}, &r0, &r1)
+	return r0, r1
+}
+
+// Use implements skytable.Skytable.
+func (m *Mock) Use(ctx context.Context, path string) error {
+	var r0 error
+	m.call("Use", []any{ctx, path}, &r0)
+	return r0
+}
+
+// InspectKeyspaces implements skytable.Skytable.
+func (m *Mock) InspectKeyspaces(ctx context.Context) (*protocol.TypedArray, error) {
+	var r0 *protocol.TypedArray
+	var r1 error
+	m.call("InspectKeyspaces", []any{ctx}, &r0, &r1)
+	return r0, r1
+}
+
+// CreateKeyspace implements skytable.Skytable.
+func (m *Mock) CreateKeyspace(ctx context.Context, name string) error {
+	var r0 error
+	m.call("CreateKeyspace", []any{ctx, name}, &r0)
+	return r0
+}
+
+// DropKeyspace implements skytable.Skytable.
+func (m *Mock) DropKeyspace(ctx context.Context, name string) error {
+	var r0 error
+	m.call("DropKeyspace", []any{ctx, name}, &r0)
+	return r0
+}
+
+// InspectKeyspace implements skytable.Skytable.
+func (m *Mock) InspectKeyspace(ctx context.Context, name string) (*protocol.TypedArray, error) {
+	var r0 *protocol.TypedArray
+	var r1 error
+	m.call("InspectKeyspace", []any{ctx, name}, &r0, &r1)
+	return r0, r1
+}
+
+// CreateTable implements skytable.Skytable.
+func (m *Mock) CreateTable(ctx context.Context, path string, modelDesc any) error {
+	var r0 error
+	m.call("CreateTable", []any{ctx, path, modelDesc}, &r0)
+	return r0
+}
+
+// DropTable implements skytable.Skytable.
+func (m *Mock) DropTable(ctx context.Context, path string) error {
+	var r0 error
+	m.call("DropTable", []any{ctx, path}, &r0)
+	return r0
+}
+
+// InspectTable implements skytable.Skytable.
+func (m *Mock) InspectTable(ctx context.Context, path string) (protocol.ModelDescription, error) {
+	var r0 protocol.ModelDescription
+	var r1 error
+	m.call("InspectTable", []any{ctx, path}, &r0, &r1)
+	return r0, r1
+}
+
+// SysInfoVersion implements skytable.Skytable.
+func (m *Mock) SysInfoVersion(ctx context.Context) (string, error) {
+	var r0 string
+	var r1 error
+	m.call("SysInfoVersion", []any{ctx}, &r0, &r1)
+	return r0, r1
+}
+
+// SysInfoProtocol implements skytable.Skytable.
+func (m *Mock) SysInfoProtocol(ctx context.Context) (string, error) {
+	var r0 string
+	var r1 error
+	m.call("SysInfoProtocol", []any{ctx}, &r0, &r1)
+	return r0, r1
+}
+
+// SysInfoProtoVer implements skytable.Skytable.
+func (m *Mock) SysInfoProtoVer(ctx context.Context) (float32, error) {
+	var r0 float32
+	var r1 error
+	m.call("SysInfoProtoVer", []any{ctx}, &r0, &r1)
+	return r0, r1
+}
+
+// SysMetricHealth implements skytable.Skytable.
+func (m *Mock) SysMetricHealth(ctx context.Context) (bool, error) {
+	var r0 bool
+	var r1 error
+	m.call("SysMetricHealth", []any{ctx}, &r0, &r1)
+	return r0, r1
+}
+
+// SysMetricStorage implements skytable.Skytable.
+func (m *Mock) SysMetricStorage(ctx context.Context) (uint64, error) {
+	var r0 uint64
+	var r1 error
+	m.call("SysMetricStorage", []any{ctx}, &r0, &r1)
+	return r0, r1
+}
+
+// MKSnap implements skytable.Skytable.
+func (m *Mock) MKSnap(ctx context.Context, name string) error {
+	var r0 error
+	m.call("MKSnap", []any{ctx, name}, &r0)
+	return r0
+}
+
+// WhereAmI implements skytable.Skytable.
+func (m *Mock) WhereAmI(ctx context.Context) (string, error) {
+	var r0 string
+	var r1 error
+	m.call("WhereAmI", []any{ctx}, &r0, &r1)
+	return r0, r1
+}
+
+// DBSize implements skytable.Skytable.
+func (m *Mock) DBSize(ctx context.Context, entity string) (uint64, error) {
+	var r0 uint64
+	var r1 error
+	m.call("DBSize", []any{ctx, entity}, &r0, &r1)
+	return r0, r1
+}
+
+// KeyLen implements skytable.Skytable.
+func (m *Mock) KeyLen(ctx context.Context, key string) (uint64, error) {
+	var r0 uint64
+	var r1 error
+	m.call("KeyLen", []any{ctx, key}, &r0, &r1)
+	return r0, r1
+}
+
+// FlushDB implements skytable.Skytable.
+func (m *Mock) FlushDB(ctx context.Context, entity string) error {
+	var r0 error
+	m.call("FlushDB", []any{ctx, entity}, &r0)
+	return r0
+}
+
+// LSKeys implements skytable.Skytable.
+func (m *Mock) LSKeys(ctx context.Context, entity string, limit uint64) (*protocol.TypedArray, error) {
+	var r0 *protocol.TypedArray
+	var r1 error
+	m.call("LSKeys", []any{ctx, entity, limit}, &r0, &r1)
+	return r0, r1
+}
@@ -0,0 +1,808 @@
+package skytabletest
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type session struct {
+	srv   *Server
+	ks    string
+	table string // "" if only a keyspace is in use
+	user  string // "" if not logged in
+}
+
+type handler func(s *session, args [][]byte, w *respWriter)
+
+var handlers map[string]handler
+
+func init() {
+	handlers = map[string]handler{
+		"HEYA":     (*session).heya,
+		"AUTH":     (*session).auth,
+		"SYS":      (*session).sys,
+		"GET":      (*session).get,
+		"SET":      (*session).set,
+		"UPDATE":   (*session).update,
+		"DEL":      (*session).del,
+		"EXISTS":   (*session).exists,
+		"POP":      (*session).pop,
+		"MGET":     (*session).mget,
+		"MPOP":     (*session).mpop,
+		"MSET":     (*session).mset,
+		"USET":     (*session).uset,
+		"MUPDATE":  (*session).mupdate,
+		"SSET":     (*session).sset,
+		"SUPDATE":  (*session).supdate,
+		"SDEL":     (*session).sdel,
+		"KEYLEN":   (*session).keylen,
+		"DBSIZE":   (*session).dbsize,
+		"FLUSHDB":  (*session).flushdb,
+		"LSKEYS":   (*session).lskeys,
+		"LGET":     (*session).lget,
+		"LMOD":     (*session).lmod,
+		"LSET":     (*session).lset,
+		"CREATE":   (*session).create,
+		"DROP":     (*session).drop,
+		"USE":      (*session).use,
+		"INSPECT":  (*session).inspect,
+		"WHEREAMI": (*session).whereami,
+		"MKSNAP":   (*session).mksnap,
+	}
+}
+
+func (s *session) exec(args [][]byte, w *respWriter) {
+	name := strings.ToUpper(string(args[0]))
+	h, found := handlers[name]
+	if !found {
+		w.errStr(errStrUnknownAction)
+		return
+	}
+
+	s.srv.mu.Lock()
+	defer s.srv.mu.Unlock()
+
+	if s.srv.opts.Auth && s.user == "" && name != "AUTH" {
+		w.code(respBadCredentials)
+		return
+	}
+
+	h(s, args[1:], w)
+}
+
+// resolveTable resolves "keyspace:table", "table" (in the current keyspace) or "" (the current table).
+func (s *session) resolveTable(entity string) (*table, string) {
+	ksName, tName := s.ks, s.table
+	if entity != "" {
+		if i := strings.IndexByte(entity, ':'); i >= 0 {
+			ksName, tName = entity[:i], entity[i+1:]
+		} else {
+			tName = entity
+		}
+	}
+
+	if tName == "" {
+		return nil, errStrDefaultUnset
+	}
+
+	ks, found := s.srv.keyspaces[ksName]
+	if !found {
+		return nil, errStrContainerNotFound
+	}
+
+	t, found := ks.tables[tName]
+	if !found {
+		return nil, errStrContainerNotFound
+	}
+
+	return t, ""
+}
+
+// kvTable returns the current table if it stores str/binstr values, or writes the error.
+func (s *session) kvTable(w *respWriter) *table {
+	t, errStr := s.resolveTable("")
+	if errStr != "" {
+		w.errStr(errStr)
+		return nil
+	}
+
+	if t.model.list {
+		w.errStr(errStrWrongModel)
+		return nil
+	}
+
+	return t
+}
+
+// listTable returns the current table if it stores lists, or writes the error.
+func (s *session) listTable(w *respWriter) *table {
+	t, errStr := s.resolveTable("")
+	if errStr != "" {
+		w.errStr(errStr)
+		return nil
+	}
+
+	if !t.model.list {
+		w.errStr(errStrWrongModel)
+		return nil
+	}
+
+	return t
+}
+
+// checkKeys writes an encoding error and returns false if any key is not acceptable by the table.
+func checkKeys(t *table, w *respWriter, keys ...[]byte) bool {
+	for _, k := range keys {
+		if !t.model.keyType.accepts(k) {
+			w.code(respEncodingError)
+			return false
+		}
+	}
+	return true
+}
+
+// checkPairs validates the arguments as key-value pairs for the table.
+func checkPairs(t *table, w *respWriter, args [][]byte) bool {
+	if len(args) == 0 || len(args)%2 != 0 {
+		w.code(respActionError)
+		return false
+	}
+
+	for i := 0; i < len(args); i += 2 {
+		if !t.model.keyType.accepts(args[i]) || !t.model.valueType.accepts(args[i+1]) {
+			w.code(respEncodingError)
+			return false
+		}
+	}
+	return true
+}
+
+func parseUint(b []byte) (uint64, bool) {
+	u, err := strconv.ParseUint(string(b), 10, 64)
+	return u, err == nil
+}
+
+func (s *session) heya(args [][]byte, w *respWriter) {
+	switch len(args) {
+	case 0:
+		w.str("HEY!")
+	case 1:
+		w.str(string(args[0]))
+	default:
+		w.code(respActionError)
+	}
+}
+
+func (s *session) sys(args [][]byte, w *respWriter) {
+	if len(args) != 2 {
+		w.code(respActionError)
+		return
+	}
+
+	switch strings.ToUpper(string(args[0])) + " " + strings.ToUpper(string(args[1])) {
+	case "INFO VERSION":
+		w.str(Version)
+	case "INFO PROTOCOL":
+		w.str(ProtoVer)
+	case "INFO PROTOVER":
+		w.float(1.1)
+	case "METRIC HEALTH":
+		w.str("good")
+	case "METRIC STORAGE":
+		w.uint(0)
+	default:
+		w.code(respActionError)
+	}
+}
+
+func (s *session) get(args [][]byte, w *respWriter) {
+	if len(args) != 1 {
+		w.code(respActionError)
+		return
+	}
+
+	t := s.kvTable(w)
+	if t == nil {
+		return
+	}
+
+	v, found := t.kv[string(args[0])]
+	if !found {
+		w.code(respNil)
+		return
+	}
+	w.value(t.model.valueType, v)
+}
+
+func (s *session) set(args [][]byte, w *respWriter) {
+	if len(args) != 2 {
+		w.code(respActionError)
+		return
+	}
+
+	t := s.kvTable(w)
+	if t == nil || !checkPairs(t, w, args) {
+		return
+	}
+
+	if _, found := t.kv[string(args[0])]; found {
+		w.code(respOverwriteError)
+		return
+	}
+
+	t.kv[string(args[0])] = copyBytes(args[1])
+	w.code(respOkay)
+}
+
+func (s *session) update(args [][]byte, w *respWriter) {
+	if len(args) != 2 {
+		w.code(respActionError)
+		return
+	}
+
+	t := s.kvTable(w)
+	if t == nil || !checkPairs(t, w, args) {
+		return
+	}
+
+	if _, found := t.kv[string(args[0])]; !found {
+		w.code(respNil)
+		return
+	}
+
+	t.kv[string(args[0])] = copyBytes(args[1])
+	w.code(respOkay)
+}
+
+func (s *session) del(args [][]byte, w *respWriter) {
+	if len(args) == 0 {
+		w.code(respActionError)
+		return
+	}
+
+	t, errStr := s.resolveTable("")
+	if errStr != "" {
+		w.errStr(errStr)
+		return
+	}
+
+	deleted := uint64(0)
+	for _, k := range args {
+		if t.model.list {
+			if _, found := t.lists[string(k)]; found {
+				delete(t.lists, string(k))
+				deleted++
+			}
+		} else if _, found := t.kv[string(k)]; found {
+			delete(t.kv, string(k))
+			deleted++
+		}
+	}
+	w.uint(deleted)
+}
+
+func (s *session) exists(args [][]byte, w *respWriter) {
+	if len(args) == 0 {
+		w.code(respActionError)
+		return
+	}
+
+	t, errStr := s.resolveTable("")
+	if errStr != "" {
+		w.errStr(errStr)
+		return
+	}
+
+	existing := uint64(0)
+	for _, k := range args {
+		if t.model.list {
+			if _, found := t.lists[string(k)]; found {
+				existing++
+			}
+		} else if _, found := t.kv[string(k)]; found {
+			existing++
+		}
+	}
+	w.uint(existing)
+}
+
+func (s *session) pop(args [][]byte, w *respWriter) {
+	if len(args) != 1 {
+		w.code(respActionError)
+		return
+	}
+
+	t := s.kvTable(w)
+	if t == nil {
+		return
+	}
+
+	v, found := t.kv[string(args[0])]
+	if !found {
+		w.code(respNil)
+		return
+	}
+
+	delete(t.kv, string(args[0]))
+	w.value(t.model.valueType, v)
+}
+
+func (s *session) mget(args [][]byte, w *respWriter) {
+	if len(args) == 0 {
+		w.code(respActionError)
+		return
+	}
+
+	t := s.kvTable(w)
+	if t == nil {
+		return
+	}
+
+	values := make([][]byte, len(args))
+	for i, k := range args {
+		values[i] = t.kv[string(k)]
+	}
+	w.typedArray(t.model.valueType, values)
+}
+
+func (s *session) mpop(args [][]byte, w *respWriter) {
+	if len(args) == 0 {
+		w.code(respActionError)
+		return
+	}
+
+	t := s.kvTable(w)
+	if t == nil {
+		return
+	}
+
+	values := make([][]byte, len(args))
+	for i, k := range args {
+		values[i] = t.kv[string(k)]
+		delete(t.kv, string(k))
+	}
+	w.typedArray(t.model.valueType, values)
+}
+
+func (s *session) mset(args [][]byte, w *respWriter) {
+	t := s.kvTable(w)
+	if t == nil || !checkPairs(t, w, args) {
+		return
+	}
+
+	set := uint64(0)
+	for i := 0; i < len(args); i += 2 {
+		if _, found := t.kv[string(args[i])]; !found {
+			t.kv[string(args[i])] = copyBytes(args[i+1])
+			set++
+		}
+	}
+	w.uint(set)
+}
+
+func (s *session) uset(args [][]byte, w *respWriter) {
+	t := s.kvTable(w)
+	if t == nil || !checkPairs(t, w, args) {
+		return
+	}
+
+	for i := 0; i < len(args); i += 2 {
+		t.kv[string(args[i])] = copyBytes(args[i+1])
+	}
+	w.uint(uint64(len(args) / 2))
+}
+
+func (s *session) mupdate(args [][]byte, w *respWriter) {
+	t := s.kvTable(w)
+	if t == nil || !checkPairs(t, w, args) {
+		return
+	}
+
+	updated := uint64(0)
+	for i := 0; i < len(args); i += 2 {
+		if _, found := t.kv[string(args[i])]; found {
+			t.kv[string(args[i])] = copyBytes(args[i+1])
+			updated++
+		}
+	}
+	w.uint(updated)
+}
+
+func (s *session) sset(args [][]byte, w *respWriter) {
+	t := s.kvTable(w)
+	if t == nil || !checkPairs(t, w, args) {
+		return
+	}
+
+	for i := 0; i < len(args); i += 2 {
+		if _, found := t.kv[string(args[i])]; found {
+			w.code(respOverwriteError)
+			return
+		}
+	}
+
+	for i := 0; i < len(args); i += 2 {
+		t.kv[string(args[i])] = copyBytes(args[i+1])
+	}
+	w.code(respOkay)
+}
+
+func (s *session) supdate(args [][]byte, w *respWriter) {
+	t := s.kvTable(w)
+	if t == nil || !checkPairs(t, w, args) {
+		return
+	}
+
+	for i := 0; i < len(args); i += 2 {
+		if _, found := t.kv[string(args[i])]; !found {
+			w.code(respNil)
+			return
+		}
+	}
+
+	for i := 0; i < len(args); i += 2 {
+		t.kv[string(args[i])] = copyBytes(args[i+1])
+	}
+	w.code(respOkay)
+}
+
+func (s *session) sdel(args [][]byte, w *respWriter) {
+	if len(args) == 0 {
+		w.code(respActionError)
+		return
+	}
+
+	t := s.kvTable(w)
+	if t == nil {
+		return
+	}
+
+	for _, k := range args {
+		if _, found := t.kv[string(k)]; !found {
+			w.code(respNil)
+			return
+		}
+	}
+
+	for _, k := range args {
+		delete(t.kv, string(k))
+	}
+	w.code(respOkay)
+}
+
+func (s *session) keylen(args [][]byte, w *respWriter) {
+	if len(args) != 1 {
+		w.code(respActionError)
+		return
+	}
+
+	t := s.kvTable(w)
+	if t == nil {
+		return
+	}
+
+	v, found := t.kv[string(args[0])]
+	if !found {
+		w.code(respNil)
+		return
+	}
+	w.uint(uint64(len(v)))
+}
+
+func (s *session) dbsize(args [][]byte, w *respWriter) {
+	if len(args) > 1 {
+		w.code(respActionError)
+		return
+	}
+
+	entity := ""
+	if len(args) == 1 {
+		entity = string(args[0])
+	}
+
+	t, errStr := s.resolveTable(entity)
+	if errStr != "" {
+		w.errStr(errStr)
+		return
+	}
+	w.uint(uint64(t.size()))
+}
+
+func (s *session) flushdb(args [][]byte, w *respWriter) {
+	if len(args) > 1 {
+		w.code(respActionError)
+		return
+	}
+
+	entity := ""
+	if len(args) == 1 {
+		entity = string(args[0])
+	}
+
+	t, errStr := s.resolveTable(entity)
+	if errStr != "" {
+		w.errStr(errStr)
+		return
+	}
+
+	t.flush()
+	w.code(respOkay)
+}
+
+// LSKEYS [entity] [limit], where limit defaults to 10
+func (s *session) lskeys(args [][]byte, w *respWriter) {
+	entity, limit := "", uint64(10)
+	switch len(args) {
+	case 0:
+	case 1:
+		if l, isLimit := parseUint(args[0]); isLimit {
+			limit = l
+		} else {
+			entity = string(args[0])
+		}
+	case 2:
+		l, isLimit := parseUint(args[1])
+		if !isLimit {
+			w.code(respWrongtypeError)
+			return
+		}
+		entity, limit = string(args[0]), l
+	default:
+		w.code(respActionError)
+		return
+	}
+
+	t, errStr := s.resolveTable(entity)
+	if errStr != "" {
+		w.errStr(errStr)
+		return
+	}
+
+	keys := t.keys()
+	if uint64(len(keys)) > limit {
+		keys = keys[:limit]
+	}
+
+	arr := make([][]byte, len(keys))
+	for i, k := range keys {
+		arr[i] = []byte(k)
+	}
+	w.typedArray(t.model.keyType, arr)
+}
+
+func (s *session) create(args [][]byte, w *respWriter) {
+	if len(args) < 2 {
+		w.code(respActionError)
+		return
+	}
+
+	switch strings.ToUpper(string(args[0])) {
+	case "KEYSPACE":
+		if len(args) != 2 {
+			w.code(respActionError)
+			return
+		}
+
+		name := string(args[1])
+		if !validContainerName(name) {
+			w.errStr(errStrBadContainerName)
+			return
+		}
+		if _, found := s.srv.keyspaces[name]; found {
+			w.errStr(errStrAlreadyExists)
+			return
+		}
+
+		s.srv.keyspaces[name] = &keyspace{tables: make(map[string]*table)}
+		w.code(respOkay)
+	case "TABLE":
+		if len(args) != 3 && len(args) != 4 {
+			w.code(respActionError)
+			return
+		}
+
+		ksName, tName := s.ks, string(args[1])
+		if i := strings.IndexByte(tName, ':'); i >= 0 {
+			ksName, tName = tName[:i], tName[i+1:]
+		}
+		if !validContainerName(tName) {
+			w.errStr(errStrBadContainerName)
+			return
+		}
+
+		model, valid := parseModel(string(args[2]))
+		if !valid {
+			w.errStr(errStrUnknownModel)
+			return
+		}
+
+		volatile := false
+		if len(args) == 4 {
+			if strings.ToLower(string(args[3])) != "volatile" {
+				w.errStr(errStrUnknownProperty)
+				return
+			}
+			volatile = true
+		}
+
+		ks, found := s.srv.keyspaces[ksName]
+		if !found {
+			w.errStr(errStrContainerNotFound)
+			return
+		}
+		if _, found := ks.tables[tName]; found {
+			w.errStr(errStrAlreadyExists)
+			return
+		}
+
+		ks.tables[tName] = newTable(model, volatile)
+		w.code(respOkay)
+	default:
+		w.errStr(errStrUnknownDDLQuery)
+	}
+}
+
+func (s *session) drop(args [][]byte, w *respWriter) {
+	if len(args) < 2 {
+		w.code(respActionError)
+		return
+	}
+
+	switch strings.ToUpper(string(args[0])) {
+	case "KEYSPACE":
+		if len(args) > 3 {
+			w.code(respActionError)
+			return
+		}
+
+		name := string(args[1])
+		force := len(args) == 3 && strings.ToUpper(string(args[2])) == "FORCE"
+		if name == DefaultKS || name == "system" {
+			w.errStr(errStrProtectedObject)
+			return
+		}
+
+		ks, found := s.srv.keyspaces[name]
+		if !found {
+			w.errStr(errStrContainerNotFound)
+			return
+		}
+		if (len(ks.tables) > 0 && !force) || s.ks == name {
+			w.errStr(errStrStillInUse)
+			return
+		}
+
+		delete(s.srv.keyspaces, name)
+		w.code(respOkay)
+	case "TABLE":
+		if len(args) != 2 {
+			w.code(respActionError)
+			return
+		}
+
+		ksName, tName := s.ks, string(args[1])
+		if i := strings.IndexByte(tName, ':'); i >= 0 {
+			ksName, tName = tName[:i], tName[i+1:]
+		}
+		if ksName == DefaultKS && tName == DefaultKS {
+			w.errStr(errStrProtectedObject)
+			return
+		}
+
+		ks, found := s.srv.keyspaces[ksName]
+		if !found {
+			w.errStr(errStrContainerNotFound)
+			return
+		}
+		if _, found := ks.tables[tName]; !found {
+			w.errStr(errStrContainerNotFound)
+			return
+		}
+		if s.ks == ksName && s.table == tName {
+			w.errStr(errStrStillInUse)
+			return
+		}
+
+		delete(ks.tables, tName)
+		w.code(respOkay)
+	default:
+		w.errStr(errStrUnknownDDLQuery)
+	}
+}
+
+func (s *session) use(args [][]byte, w *respWriter) {
+	if len(args) != 1 {
+		w.code(respActionError)
+		return
+	}
+
+	ksName, tName := string(args[0]), ""
+	if i := strings.IndexByte(ksName, ':'); i >= 0 {
+		ksName, tName = ksName[:i], ksName[i+1:]
+	}
+
+	ks, found := s.srv.keyspaces[ksName]
+	if !found {
+		w.errStr(errStrContainerNotFound)
+		return
+	}
+	if tName != "" {
+		if _, found := ks.tables[tName]; !found {
+			w.errStr(errStrContainerNotFound)
+			return
+		}
+	}
+
+	s.ks, s.table = ksName, tName
+	w.code(respOkay)
+}
+
+func (s *session) inspect(args [][]byte, w *respWriter) {
+	if len(args) == 0 || len(args) > 2 {
+		w.code(respActionError)
+		return
+	}
+
+	switch strings.ToUpper(string(args[0])) {
+	case "KEYSPACES":
+		if len(args) != 1 {
+			w.code(respActionError)
+			return
+		}
+
+		names := make([]string, 0, len(s.srv.keyspaces))
+		for n := range s.srv.keyspaces {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		w.strArray(names)
+	case "KEYSPACE":
+		name := s.ks
+		if len(args) == 2 {
+			name = string(args[1])
+		}
+
+		ks, found := s.srv.keyspaces[name]
+		if !found {
+			w.errStr(errStrContainerNotFound)
+			return
+		}
+		w.strArray(ks.tableNames())
+	case "TABLE":
+		entity := ""
+		if len(args) == 2 {
+			entity = string(args[1])
+		}
+
+		t, errStr := s.resolveTable(entity)
+		if errStr != "" {
+			w.errStr(errStr)
+			return
+		}
+		w.str(t.describe())
+	default:
+		w.errStr(errStrUnknownInspect)
+	}
+}
+
+func (s *session) whereami(args [][]byte, w *respWriter) {
+	if len(args) != 0 {
+		w.code(respActionError)
+		return
+	}
+
+	if s.table == "" {
+		w.typedNonNullArray(s.ks)
+	} else {
+		w.typedNonNullArray(s.ks, s.table)
+	}
+}
+
+func (s *session) mksnap(args [][]byte, w *respWriter) {
+	if len(args) > 1 {
+		w.code(respActionError)
+		return
+	}
+	w.code(respOkay)
+}
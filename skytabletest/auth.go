@@ -0,0 +1,127 @@
+package skytabletest
+
+import (
+	"sort"
+	"strings"
+)
+
+// AUTH <LOGIN <user> <token> | LOGOUT | CLAIM <origin> | ADDUSER <user> | DELUSER <user> | RESTORE [<origin>] <user> | LISTUSER | WHOAMI>
+func (s *session) auth(args [][]byte, w *respWriter) {
+	if !s.srv.opts.Auth {
+		w.errStr(errStrAuthDisabled)
+		return
+	}
+
+	if len(args) == 0 {
+		w.code(respActionError)
+		return
+	}
+
+	users := s.srv.users
+	switch strings.ToUpper(string(args[0])) {
+	case "LOGIN":
+		if len(args) != 3 {
+			w.code(respActionError)
+			return
+		}
+		token, found := users[string(args[1])]
+		if !found || token != string(args[2]) {
+			w.code(respBadCredentials)
+			return
+		}
+		s.user = string(args[1])
+		w.code(respOkay)
+	case "LOGOUT":
+		if s.user == "" {
+			w.code(respBadCredentials)
+			return
+		}
+		s.user = ""
+		w.code(respOkay)
+	case "CLAIM":
+		if len(args) != 2 {
+			w.code(respActionError)
+			return
+		}
+		if string(args[1]) != s.srv.opts.OriginKey {
+			w.code(respBadCredentials)
+			return
+		}
+		users[RootUser] = newToken()
+		w.str(users[RootUser])
+	case "ADDUSER":
+		if len(args) != 2 {
+			w.code(respActionError)
+			return
+		}
+		if s.user != RootUser {
+			w.code(respAuthnRealm)
+			return
+		}
+		if _, found := users[string(args[1])]; found {
+			w.errStr(errStrAlreadyExists)
+			return
+		}
+		users[string(args[1])] = newToken()
+		w.str(users[string(args[1])])
+	case "DELUSER":
+		if len(args) != 2 {
+			w.code(respActionError)
+			return
+		}
+		if s.user != RootUser || string(args[1]) == RootUser {
+			w.code(respAuthnRealm)
+			return
+		}
+		if _, found := users[string(args[1])]; !found {
+			w.code(respNil)
+			return
+		}
+		delete(users, string(args[1]))
+		w.code(respOkay)
+	case "RESTORE":
+		var username string
+		switch len(args) {
+		case 2:
+			if s.user != RootUser {
+				w.code(respAuthnRealm)
+				return
+			}
+			username = string(args[1])
+		case 3:
+			if string(args[1]) != s.srv.opts.OriginKey {
+				w.code(respBadCredentials)
+				return
+			}
+			username = string(args[2])
+		default:
+			w.code(respActionError)
+			return
+		}
+		if _, found := users[username]; !found {
+			w.code(respBadCredentials)
+			return
+		}
+		users[username] = newToken()
+		w.str(users[username])
+	case "LISTUSER":
+		if s.user != RootUser {
+			w.code(respAuthnRealm)
+			return
+		}
+		names := make([]string, 0, len(users))
+		for n := range users {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		w.strArray(names)
+	case "WHOAMI":
+		if s.user == "" {
+			w.code(respBadCredentials)
+			return
+		}
+		w.str(s.user)
+	default:
+		w.code(respActionError)
+	}
+}
@@ -0,0 +1,238 @@
+package skytabletest
+
+import "strings"
+
+// LGET <list> [LIMIT <n> | LEN | VALUEAT <i> | FIRST | LAST | RANGE <from> [<to>]]
+func (s *session) lget(args [][]byte, w *respWriter) {
+	if len(args) == 0 {
+		w.code(respActionError)
+		return
+	}
+
+	t := s.listTable(w)
+	if t == nil {
+		return
+	}
+
+	list, found := t.lists[string(args[0])]
+	if !found {
+		w.code(respNil)
+		return
+	}
+
+	if len(args) == 1 {
+		w.typedArray(t.model.valueType, list)
+		return
+	}
+
+	switch strings.ToUpper(string(args[1])) {
+	case "LIMIT":
+		limit, valid := s.uintArg(args, 2, 3, w)
+		if !valid {
+			return
+		}
+		if uint64(len(list)) > limit {
+			list = list[:limit]
+		}
+		w.typedArray(t.model.valueType, list)
+	case "LEN":
+		if len(args) != 2 {
+			w.code(respActionError)
+			return
+		}
+		w.uint(uint64(len(list)))
+	case "VALUEAT":
+		i, valid := s.uintArg(args, 2, 3, w)
+		if !valid {
+			return
+		}
+		if i >= uint64(len(list)) {
+			w.errStr(errStrBadListIndex)
+			return
+		}
+		w.value(t.model.valueType, list[i])
+	case "FIRST", "LAST":
+		if len(args) != 2 {
+			w.code(respActionError)
+			return
+		}
+		if len(list) == 0 {
+			w.errStr(errStrListIsEmpty)
+			return
+		}
+		if strings.ToUpper(string(args[1])) == "FIRST" {
+			w.value(t.model.valueType, list[0])
+		} else {
+			w.value(t.model.valueType, list[len(list)-1])
+		}
+	case "RANGE":
+		if len(args) != 3 && len(args) != 4 {
+			w.code(respActionError)
+			return
+		}
+		from, valid := parseUint(args[2])
+		if !valid {
+			w.code(respWrongtypeError)
+			return
+		}
+		to := uint64(len(list))
+		if len(args) == 4 {
+			if to, valid = parseUint(args[3]); !valid {
+				w.code(respWrongtypeError)
+				return
+			}
+		}
+		if from > to || to > uint64(len(list)) {
+			w.errStr(errStrBadListIndex)
+			return
+		}
+		w.typedArray(t.model.valueType, list[from:to])
+	default:
+		w.code(respActionError)
+	}
+}
+
+// LMOD <list> <PUSH <v>... | INSERT <i> <v> | POP [<i>] | REMOVE <i> | CLEAR>
+func (s *session) lmod(args [][]byte, w *respWriter) {
+	if len(args) < 2 {
+		w.code(respActionError)
+		return
+	}
+
+	t := s.listTable(w)
+	if t == nil {
+		return
+	}
+
+	name := string(args[0])
+	list, found := t.lists[name]
+	if !found {
+		w.code(respNil)
+		return
+	}
+
+	switch strings.ToUpper(string(args[1])) {
+	case "PUSH":
+		if len(args) < 3 {
+			w.code(respActionError)
+			return
+		}
+		for _, e := range args[2:] {
+			if !t.model.valueType.accepts(e) {
+				w.code(respEncodingError)
+				return
+			}
+		}
+		for _, e := range args[2:] {
+			list = append(list, copyBytes(e))
+		}
+		t.lists[name] = list
+		w.code(respOkay)
+	case "INSERT":
+		i, valid := s.uintArg(args, 2, 4, w)
+		if !valid {
+			return
+		}
+		if i > uint64(len(list)) {
+			w.errStr(errStrBadListIndex)
+			return
+		}
+		if !t.model.valueType.accepts(args[3]) {
+			w.code(respEncodingError)
+			return
+		}
+		list = append(list, nil)
+		copy(list[i+1:], list[i:])
+		list[i] = copyBytes(args[3])
+		t.lists[name] = list
+		w.code(respOkay)
+	case "POP":
+		if len(args) > 3 {
+			w.code(respActionError)
+			return
+		}
+		if len(list) == 0 {
+			w.errStr(errStrListIsEmpty)
+			return
+		}
+		i := uint64(len(list) - 1)
+		if len(args) == 3 {
+			var valid bool
+			if i, valid = s.uintArg(args, 2, 3, w); !valid {
+				return
+			}
+			if i >= uint64(len(list)) {
+				w.errStr(errStrBadListIndex)
+				return
+			}
+		}
+		v := list[i]
+		t.lists[name] = append(list[:i], list[i+1:]...)
+		w.value(t.model.valueType, v)
+	case "REMOVE":
+		i, valid := s.uintArg(args, 2, 3, w)
+		if !valid {
+			return
+		}
+		if i >= uint64(len(list)) {
+			w.errStr(errStrBadListIndex)
+			return
+		}
+		t.lists[name] = append(list[:i], list[i+1:]...)
+		w.code(respOkay)
+	case "CLEAR":
+		if len(args) != 2 {
+			w.code(respActionError)
+			return
+		}
+		t.lists[name] = [][]byte{}
+		w.code(respOkay)
+	default:
+		w.code(respActionError)
+	}
+}
+
+// LSET <list> [<v>...]
+func (s *session) lset(args [][]byte, w *respWriter) {
+	if len(args) == 0 {
+		w.code(respActionError)
+		return
+	}
+
+	t := s.listTable(w)
+	if t == nil || !checkKeys(t, w, args[0]) {
+		return
+	}
+
+	if _, found := t.lists[string(args[0])]; found {
+		w.code(respOverwriteError)
+		return
+	}
+
+	list := make([][]byte, 0, len(args)-1)
+	for _, e := range args[1:] {
+		if !t.model.valueType.accepts(e) {
+			w.code(respEncodingError)
+			return
+		}
+		list = append(list, copyBytes(e))
+	}
+
+	t.lists[string(args[0])] = list
+	w.code(respOkay)
+}
+
+// uintArg parses args[i] as an index, if the action has exactly `argc` arguments.
+func (s *session) uintArg(args [][]byte, i, argc int, w *respWriter) (uint64, bool) {
+	if len(args) != argc {
+		w.code(respActionError)
+		return 0, false
+	}
+
+	u, valid := parseUint(args[i])
+	if !valid {
+		w.code(respWrongtypeError)
+		return 0, false
+	}
+	return u, true
+}
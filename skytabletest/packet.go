@@ -0,0 +1,215 @@
+package skytabletest
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+)
+
+var errMalformed = errors.New("malformed packet")
+
+// Limits of the sizes declared in query packets, exceeding them results in a packet error.
+const (
+	maxActions  = 1 << 16
+	maxElements = 1 << 20
+	maxElement  = 1 << 28
+)
+
+const (
+	respOkay           = 0
+	respNil            = 1
+	respOverwriteError = 2
+	respActionError    = 3
+	respPacketError    = 4
+	respServerError    = 5
+	respWrongtypeError = 7
+	respEncodingError  = 9
+	respBadCredentials = 10
+	respAuthnRealm     = 11
+)
+
+const (
+	errStrAlreadyExists     = "err-already-exists"
+	errStrContainerNotFound = "container-not-found"
+	errStrDefaultUnset      = "default-container-unset"
+	errStrStillInUse        = "still-in-use"
+	errStrProtectedObject   = "err-protected-object"
+	errStrBadContainerName  = "bad-container-name"
+	errStrUnknownModel      = "unknown-model"
+	errStrUnknownProperty   = "unknown-property"
+	errStrUnknownDDLQuery   = "unknown-ddl-query"
+	errStrUnknownInspect    = "unknown-inspect-query"
+	errStrWrongModel        = "wrong-model"
+	errStrBadListIndex      = "bad-list-index"
+	errStrListIsEmpty       = "list-is-empty"
+	errStrAuthDisabled      = "err-auth-disabled"
+	errStrUnknownAction     = "Unknown action"
+)
+
+func readLine(r *bufio.Reader, prefix byte) (int64, error) {
+	line, err := r.ReadSlice('\n')
+	if err != nil {
+		if err == bufio.ErrBufferFull {
+			return 0, errMalformed
+		}
+		return 0, err
+	}
+
+	line = line[:len(line)-1]
+	if prefix != 0 {
+		if len(line) == 0 || line[0] != prefix {
+			return 0, errMalformed
+		}
+		line = line[1:]
+	}
+
+	n, err := strconv.ParseInt(string(line), 10, 64)
+	if err != nil || n < 0 {
+		return 0, errMalformed
+	}
+
+	return n, nil
+}
+
+// readQuery reads a simple or pipelined query, returning the elements of each action.
+func readQuery(r *bufio.Reader) ([][][]byte, error) {
+	actions, err := readLine(r, '*')
+	if err != nil {
+		return nil, err
+	}
+
+	if actions == 0 || actions > maxActions {
+		return nil, errMalformed
+	}
+
+	query := make([][][]byte, 0, actions)
+	for i := int64(0); i < actions; i++ {
+		elements, err := readLine(r, '~')
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+
+		if elements == 0 || elements > maxElements {
+			return nil, errMalformed
+		}
+
+		args := make([][]byte, 0, elements)
+		for j := int64(0); j < elements; j++ {
+			size, err := readLine(r, 0)
+			if err != nil {
+				return nil, unexpectedEOF(err)
+			}
+
+			if size > maxElement {
+				return nil, errMalformed
+			}
+
+			e := make([]byte, size+1)
+			if _, err := io.ReadFull(r, e); err != nil {
+				return nil, unexpectedEOF(err)
+			}
+
+			if e[size] != '\n' {
+				return nil, errMalformed
+			}
+
+			args = append(args, e[:size])
+		}
+
+		query = append(query, args)
+	}
+
+	return query, nil
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// respWriter builds a response packet.
+type respWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *respWriter) reset(entries int) {
+	w.buf.Reset()
+	w.buf.WriteByte('*')
+	w.buf.WriteString(strconv.Itoa(entries))
+	w.buf.WriteByte('\n')
+}
+
+func (w *respWriter) bytes() []byte {
+	return w.buf.Bytes()
+}
+
+func (w *respWriter) sized(tsymbol byte, v []byte) {
+	if tsymbol != 0 {
+		w.buf.WriteByte(tsymbol)
+	}
+	w.buf.WriteString(strconv.Itoa(len(v)))
+	w.buf.WriteByte('\n')
+	w.buf.Write(v)
+	w.buf.WriteByte('\n')
+}
+
+func (w *respWriter) code(c int) {
+	w.sized('!', []byte(strconv.Itoa(c)))
+}
+
+func (w *respWriter) errStr(s string) {
+	w.sized('!', []byte(s))
+}
+
+func (w *respWriter) str(s string) {
+	w.sized('+', []byte(s))
+}
+
+func (w *respWriter) value(t dataType, v []byte) {
+	w.sized(t.tsymbol(), v)
+}
+
+func (w *respWriter) uint(u uint64) {
+	w.sized(':', []byte(strconv.FormatUint(u, 10)))
+}
+
+func (w *respWriter) float(f float32) {
+	w.sized('%', []byte(strconv.FormatFloat(float64(f), 'f', -1, 32)))
+}
+
+// typedArray writes a typed array, nil elements are written as NULL.
+func (w *respWriter) typedArray(t dataType, elements [][]byte) {
+	w.buf.WriteByte('@')
+	w.buf.WriteByte(t.tsymbol())
+	w.buf.WriteString(strconv.Itoa(len(elements)))
+	w.buf.WriteByte('\n')
+	for _, e := range elements {
+		if e == nil {
+			w.buf.WriteString("\x00\n")
+			continue
+		}
+		w.sized(0, e)
+	}
+}
+
+// typedNonNullArray writes a typed non-null array of strings.
+func (w *respWriter) typedNonNullArray(elements ...string) {
+	w.buf.WriteString("^+")
+	w.buf.WriteString(strconv.Itoa(len(elements)))
+	w.buf.WriteByte('\n')
+	for _, e := range elements {
+		w.sized(0, []byte(e))
+	}
+}
+
+func (w *respWriter) strArray(elements []string) {
+	arr := make([][]byte, len(elements))
+	for i, e := range elements {
+		arr[i] = []byte(e)
+	}
+	w.typedArray(typeStr, arr)
+}
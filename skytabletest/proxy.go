@@ -0,0 +1,244 @@
+package skytabletest
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// The response of Skytable to a malformed packet, before it closes the connection.
+var packetErrorResponse = []byte("*1\n!1\n4\n")
+
+type fault byte
+
+const (
+	noFault fault = iota
+	faultDropMidRequest
+	faultPacketError
+	faultTruncateResponse
+)
+
+// Proxy forwards TCP connections to a server (a [Server] or a real Skytable instance),
+// and injects faults into the traffic to test how clients deal with them.
+//
+// Faults are one-shot: each applies to the next packet sent in its direction, by whichever connection comes first.
+// Packets are approximated by reads: the driver writes a query with a single write,
+// so a packet smaller than 64 KiB is usually seen at once.
+//
+//	p, err := skytabletest.NewProxy(srv.Addr())
+//	c, err := skytable.NewConn(p.Addr())
+//	p.TruncateNextResponse(3)
+//	err = c.Heya(ctx, "") // failed to read from conn: unexpected EOF
+type Proxy struct {
+	ln     net.Listener
+	target *net.TCPAddr
+
+	mu         sync.Mutex
+	latency    time.Duration
+	reqFault   fault
+	respFault  fault
+	truncateAt int
+	conns      map[net.Conn]struct{}
+	shutdown   bool // Conns accepted after Close are closed right away
+
+	wg        sync.WaitGroup
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewProxy starts a proxy listening on 127.0.0.1 (on a random port) and forwarding to target.
+func NewProxy(target *net.TCPAddr) (*Proxy, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Proxy{
+		ln:     ln,
+		target: target,
+		conns:  make(map[net.Conn]struct{}),
+		closed: make(chan struct{}),
+	}
+
+	p.wg.Add(1)
+	go p.serve()
+
+	return p, nil
+}
+
+// Addr returns the address the proxy is listening on, clients should connect to it instead of the target.
+func (p *Proxy) Addr() *net.TCPAddr {
+	return p.ln.Addr().(*net.TCPAddr)
+}
+
+// Close stops accepting new connections, and closes all the connections.
+func (p *Proxy) Close() (err error) {
+	p.closeOnce.Do(func() {
+		close(p.closed)
+		err = p.ln.Close()
+
+		p.mu.Lock()
+		p.shutdown = true
+		p.mu.Unlock()
+		p.CloseConns()
+	})
+	p.wg.Wait()
+
+	return err
+}
+
+// CloseConns closes all the proxied connections, on both sides.
+func (p *Proxy) CloseConns() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for c := range p.conns {
+		c.Close()
+	}
+}
+
+// SetLatency delays every forwarded read by d, in both directions. 0 disables the delay.
+func (p *Proxy) SetLatency(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.latency = d
+}
+
+// DropNextRequest forwards the first half of the next request to the server,
+// then closes the connection on both sides, as if the network failed in the middle of the packet.
+func (p *Proxy) DropNextRequest() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.reqFault = faultDropMidRequest
+}
+
+// InjectPacketError answers the next request with a packet error (without forwarding it),
+// then closes the connection, as Skytable does when it receives a malformed packet.
+func (p *Proxy) InjectPacketError() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.reqFault = faultPacketError
+}
+
+// TruncateNextResponse forwards only the first n bytes of the next response, then closes the connection on both sides.
+func (p *Proxy) TruncateNextResponse(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.respFault = faultTruncateResponse
+	p.truncateAt = n
+}
+
+func (p *Proxy) serve() {
+	defer p.wg.Done()
+
+	for {
+		client, err := p.ln.Accept()
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			return
+		}
+
+		server, err := net.DialTCP("tcp", nil, p.target)
+		if err != nil {
+			client.Close()
+			continue
+		}
+
+		p.mu.Lock()
+		if p.shutdown {
+			p.mu.Unlock()
+			client.Close()
+			server.Close()
+			continue
+		}
+		p.conns[client] = struct{}{}
+		p.conns[server] = struct{}{}
+		p.wg.Add(2)
+		p.mu.Unlock()
+
+		go p.pipe(client, server, true)
+		go p.pipe(client, server, false)
+	}
+}
+
+// pipe forwards from the client to the server if toServer, otherwise from the server to the client.
+func (p *Proxy) pipe(client, server net.Conn, toServer bool) {
+	defer p.wg.Done()
+	defer p.closePair(client, server)
+
+	src, dst := server, client
+	if toServer {
+		src, dst = client, server
+	}
+
+	buf := make([]byte, 64<<10)
+	for {
+		n, err := src.Read(buf)
+		if n > 0 {
+			chunk := buf[:n]
+
+			p.mu.Lock()
+			latency := p.latency
+			f, truncateAt := p.takeFault(toServer)
+			p.mu.Unlock()
+
+			if latency > 0 {
+				time.Sleep(latency)
+			}
+
+			switch f {
+			case faultDropMidRequest:
+				dst.Write(chunk[:len(chunk)/2])
+				return
+			case faultPacketError:
+				client.Write(packetErrorResponse)
+				return
+			case faultTruncateResponse:
+				if truncateAt < len(chunk) {
+					chunk = chunk[:truncateAt]
+				}
+				dst.Write(chunk)
+				return
+			}
+
+			if _, err := dst.Write(chunk); err != nil {
+				return
+			}
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// takeFault returns and clears the pending fault of the direction. p.mu must be held.
+func (p *Proxy) takeFault(toServer bool) (fault, int) {
+	if toServer {
+		f := p.reqFault
+		p.reqFault = noFault
+		return f, 0
+	}
+
+	f := p.respFault
+	p.respFault = noFault
+	return f, p.truncateAt
+}
+
+func (p *Proxy) closePair(client, server net.Conn) {
+	client.Close()
+	server.Close()
+
+	p.mu.Lock()
+	delete(p.conns, client)
+	delete(p.conns, server)
+	p.mu.Unlock()
+}
@@ -0,0 +1,70 @@
+package skytabletest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/skytabletest"
+)
+
+func newProxy(t *testing.T, srv *skytabletest.Server) *skytabletest.Proxy {
+	p, err := skytabletest.NewProxy(srv.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { p.Close() })
+
+	return p
+}
+
+func TestProxy_Latency(t *testing.T) {
+	ctx := context.Background()
+	p := newProxy(t, newServer(t, skytabletest.Options{}))
+
+	c, err := skytable.NewConn(p.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	p.SetLatency(20 * time.Millisecond)
+
+	start := time.Now()
+	if err := c.Heya(ctx, ""); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("expecting both directions delayed but took %s", elapsed)
+	}
+}
+
+func TestProxy_FaultIsOneShot(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t, skytabletest.Options{})
+	p := newProxy(t, srv)
+
+	c, err := skytable.NewConn(p.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.EnableAutoReconnect()
+
+	p.DropNextRequest()
+	if err := c.Set(ctx, "k", "v"); err == nil {
+		t.Fatal("expecting the request to be dropped")
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := c.Heya(ctx, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Half of the packet must not be executed by the server.
+	if _, err := c.Get(ctx, "k"); err == nil {
+		t.Fatal("expecting the dropped SET not to be executed")
+	}
+}
@@ -0,0 +1,234 @@
+// Package skytabletest provides an in-memory Skytable server speaking Skyhash 1.1, for tests.
+//
+// The server implements the keymap actions, lists, keyspaces/tables (DDL), SYS and AUTH,
+// so code depending on the driver can be tested hermetically:
+//
+//	srv, err := skytabletest.NewServer(skytabletest.Options{})
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer srv.Close()
+//
+//	c, err := skytable.NewConn(srv.Addr())
+//
+// It's not meant to be a complete or performant Skytable implementation,
+// snapshots and persistence are only acknowledged, and the data is lost when closed.
+package skytabletest
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
+	"sync"
+)
+
+const (
+	Version   = "0.7.6"
+	ProtoVer  = "Skyhash-1.1"
+	RootUser  = "root"
+	DefaultKS = "default"
+)
+
+type Options struct {
+	// The address to listen on, "127.0.0.1:0" if empty.
+	Addr string
+	// Enable authn. The root user is available with [Server.RootToken],
+	// more users can be added with [Server.AddUser] or AUTH ADDUSER.
+	Auth bool
+	// The origin key used by AUTH CLAIM and AUTH RESTORE, generated if empty.
+	OriginKey string
+}
+
+type Server struct {
+	ln   net.Listener
+	opts Options
+
+	mu        sync.Mutex
+	keyspaces map[string]*keyspace
+	users     map[string]string // username -> token
+
+	connsMu  sync.Mutex
+	conns    map[net.Conn]struct{}
+	shutdown bool // Conns accepted after Close are closed right away
+
+	wg        sync.WaitGroup
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewServer starts a server listening on opts.Addr, with the "default:default" keymap(binstr,binstr) table.
+func NewServer(opts Options) (*Server, error) {
+	if opts.Addr == "" {
+		opts.Addr = "127.0.0.1:0"
+	}
+
+	if opts.OriginKey == "" {
+		opts.OriginKey = newToken()
+	}
+
+	ln, err := net.Listen("tcp", opts.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		ln:        ln,
+		opts:      opts,
+		keyspaces: make(map[string]*keyspace),
+		users:     make(map[string]string),
+		conns:     make(map[net.Conn]struct{}),
+		closed:    make(chan struct{}),
+	}
+
+	s.keyspaces[DefaultKS] = &keyspace{
+		tables: map[string]*table{
+			DefaultKS: newTable(keymapModel{keyType: typeBinstr, valueType: typeBinstr}, false),
+		},
+	}
+	if opts.Auth {
+		s.users[RootUser] = newToken()
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+
+	return s, nil
+}
+
+// Addr returns the address the server is listening on.
+func (s *Server) Addr() *net.TCPAddr {
+	return s.ln.Addr().(*net.TCPAddr)
+}
+
+// Close stops accepting new connections, and closes all the connections.
+func (s *Server) Close() (err error) {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+		err = s.ln.Close()
+
+		s.connsMu.Lock()
+		s.shutdown = true
+		s.connsMu.Unlock()
+		s.CloseClientConns()
+	})
+	s.wg.Wait()
+
+	return err
+}
+
+// CloseClientConns closes all the connections currently opened by clients, as if the server restarted.
+func (s *Server) CloseClientConns() {
+	s.connsMu.Lock()
+	defer s.connsMu.Unlock()
+
+	for c := range s.conns {
+		c.Close()
+	}
+}
+
+// RootToken returns the token of the root user, or "" if authn is disabled.
+func (s *Server) RootToken() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.users[RootUser]
+}
+
+// OriginKey returns the origin key used by AUTH CLAIM and AUTH RESTORE.
+func (s *Server) OriginKey() string {
+	return s.opts.OriginKey
+}
+
+// AddUser adds a user (or resets its token) and returns the token.
+func (s *Server) AddUser(username string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token := newToken()
+	s.users[username] = token
+	return token
+}
+
+// SetUserToken adds a user with the provided token, or overwrites the token of the user.
+func (s *Server) SetUserToken(username, token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[username] = token
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		nc, err := s.ln.Accept()
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			return
+		}
+
+		s.connsMu.Lock()
+		if s.shutdown {
+			s.connsMu.Unlock()
+			nc.Close()
+			continue
+		}
+		s.conns[nc] = struct{}{}
+		s.wg.Add(1)
+		s.connsMu.Unlock()
+
+		go s.handle(nc)
+	}
+}
+
+func (s *Server) handle(nc net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		nc.Close()
+		s.connsMu.Lock()
+		delete(s.conns, nc)
+		s.connsMu.Unlock()
+	}()
+
+	sess := &session{
+		srv:   s,
+		ks:    DefaultKS,
+		table: DefaultKS,
+	}
+	r := bufio.NewReader(nc)
+	w := &respWriter{}
+
+	for {
+		query, err := readQuery(r)
+		if err != nil {
+			if errors.Is(err, errMalformed) {
+				w.reset(1)
+				w.code(respPacketError)
+				nc.Write(w.bytes())
+			}
+			return
+		}
+
+		w.reset(len(query))
+		for _, args := range query {
+			sess.exec(args, w)
+		}
+
+		if _, err := nc.Write(w.bytes()); err != nil {
+			return
+		}
+	}
+}
+
+func newToken() string {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
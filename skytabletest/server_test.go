@@ -0,0 +1,190 @@
+package skytabletest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/action"
+	"github.com/No3371/go-skytable/protocol"
+	"github.com/No3371/go-skytable/skytabletest"
+)
+
+func newServer(t *testing.T, opts skytabletest.Options) *skytabletest.Server {
+	srv, err := skytabletest.NewServer(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { srv.Close() })
+
+	return srv
+}
+
+func TestServer_KeyMap(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t, skytabletest.Options{})
+
+	c, err := skytable.NewConn(srv.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if err := c.Set(ctx, "k", "v"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Set(ctx, "k", "v"); err != protocol.ErrCodeOverwriteError {
+		t.Fatalf("expecting Overwrite but got %v", err)
+	}
+
+	v, err := c.GetBytes(ctx, "k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(v) != "v" {
+		t.Fatalf("expecting v but got %s", v)
+	}
+
+	if _, err := c.Get(ctx, "missing"); err != protocol.ErrCodeNil {
+		t.Fatalf("expecting Nil but got %v", err)
+	}
+
+	arr, err := c.MGet(ctx, []string{"k", "missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(arr.Elements) != 2 || arr.Elements[1] != nil {
+		t.Fatalf("unexpected MGET result: %v", arr.Elements)
+	}
+
+	size, err := c.DBSize(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != 1 {
+		t.Fatalf("expecting size 1 but got %d", size)
+	}
+}
+
+func TestServer_Lists(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t, skytabletest.Options{})
+
+	c, err := skytable.NewConn(srv.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	_, err = c.BuildAndExecQuery(skytable.NewQueryPacket([]skytable.Action{
+		action.CreateTable{
+			Path:             "default:lists",
+			ModelDescription: protocol.KeyMapDescription{KeyType: protocol.DDLDataTypes_String, ValueType: protocol.DDLDataTypes_List},
+		},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Use(ctx, "default:lists"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.LSet(ctx, "l", []any{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.LModPush(ctx, "l", []any{"c"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.LModInsert(ctx, "l", 0, "z"); err != nil {
+		t.Fatal(err)
+	}
+
+	arr, err := c.LGetRange(ctx, "l", 1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(arr.Elements) != 2 || string(arr.Elements[0].([]byte)) != "a" || string(arr.Elements[1].([]byte)) != "b" {
+		t.Fatalf("unexpected LGET RANGE result: %v", arr.Elements)
+	}
+
+	popped, err := c.LModPop(ctx, "l")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(popped.Value.([]byte)) != "c" {
+		t.Fatalf("expecting c but got %v", popped.Value)
+	}
+
+	l, err := c.LGetLen(ctx, "l")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l != 3 {
+		t.Fatalf("expecting len 3 but got %d", l)
+	}
+
+	desc, err := c.InspectTable(ctx, "default:default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc.Model() != "keymap(binstr,binstr)" {
+		t.Fatalf("unexpected model: %s", desc.Model())
+	}
+}
+
+func TestServer_Auth(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t, skytabletest.Options{Auth: true})
+
+	token := srv.AddUser("user")
+
+	_, err := skytable.NewConnAuth(srv.Addr(), func() (string, string, error) {
+		return "user", "wrong", nil
+	})
+	if err == nil {
+		t.Fatal("expecting bad credentials")
+	}
+
+	c, err := skytable.NewConnAuth(srv.Addr(), func() (string, string, error) {
+		return "user", token, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	who, err := c.AuthWhoAmI(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if who != "user" {
+		t.Fatalf("expecting user but got %s", who)
+	}
+
+	if _, err := c.AuthAddUser(ctx, "another"); err == nil {
+		t.Fatal("expecting only root to be able to add users")
+	}
+}
+
+func TestServer_CloseClientConns(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t, skytabletest.Options{})
+
+	c, err := skytable.NewConn(srv.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.EnableAutoReconnect()
+
+	srv.CloseClientConns()
+
+	if err := c.Heya(ctx, ""); err == nil {
+		t.Fatal("expecting the connection to be dropped")
+	}
+
+	if err := c.Heya(ctx, ""); err != nil {
+		t.Fatalf("expecting reconnected but got %v", err)
+	}
+}
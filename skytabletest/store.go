@@ -0,0 +1,142 @@
+package skytabletest
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+type dataType byte
+
+const (
+	typeStr dataType = iota
+	typeBinstr
+)
+
+func (t dataType) String() string {
+	if t == typeStr {
+		return "str"
+	}
+	return "binstr"
+}
+
+func (t dataType) tsymbol() byte {
+	if t == typeStr {
+		return '+'
+	}
+	return '?'
+}
+
+func (t dataType) accepts(v []byte) bool {
+	return t == typeBinstr || utf8.Valid(v)
+}
+
+type keymapModel struct {
+	keyType   dataType
+	valueType dataType
+	list      bool // if true, valueType is the element type of lists
+}
+
+// keymap(str,binstr), keymap(str,list<binstr>), keymap(str,list) for list<binstr>
+var modelRegex = regexp.MustCompile(`^keymap\((str|binstr),(str|binstr|list|list<str>|list<binstr>)\)$`)
+
+func parseModel(s string) (keymapModel, bool) {
+	matches := modelRegex.FindStringSubmatch(strings.ReplaceAll(strings.ToLower(s), " ", ""))
+	if matches == nil {
+		return keymapModel{}, false
+	}
+
+	m := keymapModel{keyType: typeBinstr, valueType: typeBinstr}
+	if matches[1] == "str" {
+		m.keyType = typeStr
+	}
+
+	switch matches[2] {
+	case "str", "list<str>":
+		m.valueType = typeStr
+	}
+	m.list = strings.HasPrefix(matches[2], "list")
+
+	return m, true
+}
+
+func (m keymapModel) valueString() string {
+	if m.list {
+		return fmt.Sprintf("list<%s>", m.valueType)
+	}
+	return m.valueType.String()
+}
+
+type keyspace struct {
+	tables map[string]*table
+}
+
+func (ks *keyspace) tableNames() []string {
+	names := make([]string, 0, len(ks.tables))
+	for n := range ks.tables {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type table struct {
+	model    keymapModel
+	volatile bool
+
+	kv    map[string][]byte
+	lists map[string][][]byte
+}
+
+func newTable(model keymapModel, volatile bool) *table {
+	return &table{
+		model:    model,
+		volatile: volatile,
+		kv:       make(map[string][]byte),
+		lists:    make(map[string][][]byte),
+	}
+}
+
+// describe returns the model description as INSPECT TABLE does.
+func (t *table) describe() string {
+	return fmt.Sprintf("Keymap { data:(%s,%s), volatile:%t }", t.model.keyType, t.model.valueString(), t.volatile)
+}
+
+func (t *table) size() int {
+	if t.model.list {
+		return len(t.lists)
+	}
+	return len(t.kv)
+}
+
+func (t *table) flush() {
+	t.kv = make(map[string][]byte)
+	t.lists = make(map[string][][]byte)
+}
+
+func (t *table) keys() []string {
+	keys := make([]string, 0, t.size())
+	if t.model.list {
+		for k := range t.lists {
+			keys = append(keys, k)
+		}
+	} else {
+		for k := range t.kv {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var containerNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,63}$`)
+
+func validContainerName(name string) bool {
+	return containerNameRegex.MatchString(name)
+}
+
+func copyBytes(b []byte) []byte {
+	return append(make([]byte, 0, len(b)), b...)
+}
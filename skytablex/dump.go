@@ -0,0 +1,550 @@
+package skytablex
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/action"
+	"github.com/No3371/go-skytable/protocol"
+)
+
+// DumpVersion is the version of the format written by Dump.
+const DumpVersion = 1
+
+type DumpOptions struct {
+	Keyspaces []string // Keyspaces to dump, all the keyspaces but "system" if nil
+	BatchSize int      // Keys read per MGET, 100 if 0
+}
+
+type DumpStats struct {
+	Keyspaces int
+	Tables    int
+	Entries   int
+}
+
+// Dump writes the keyspaces, the tables and their entries to w as JSON Lines, one record per line:
+//
+//	{"type":"dump","version":1}
+//	{"type":"keyspace","keyspace":"app"}
+//	{"type":"table","table":"app:users","model":"keymap(str,binstr)","volatile":true}
+//	{"type":"entry","table":"app:users","key":"alice","value":{"base64":"/wA="}}
+//	{"type":"entry","table":"app:queues","key":"jobs","list":["a","b"]}
+//
+// Keys and values are JSON strings, or objects holding them in base64 when they are not valid UTF-8.
+//
+// The keys are listed with LSKEYS and the entries read with MGET and LGET, after USE of each table:
+// `db` is left on the last table dumped. Entries written meanwhile may be missed.
+//
+// LSKEYS has no offset to page through the keys, so all the keys of a table are listed at once and held in memory
+// while the table is dumped (the entries are streamed per BatchSize): the largest table bounds the memory Dump needs.
+func Dump(ctx context.Context, db skytable.Skytable, w io.Writer, opts DumpOptions) (stats DumpStats, err error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+
+	err = enc.Encode(dumpRecord{Type: "dump", Version: DumpVersion})
+	if err != nil {
+		return stats, fmt.Errorf("Dump(): %w", err)
+	}
+
+	keyspaces := opts.Keyspaces
+	if keyspaces == nil {
+		keyspaces, err = listKeyspaces(ctx, db)
+		if err != nil {
+			return stats, fmt.Errorf("Dump(): %w", err)
+		}
+	}
+
+	for _, ks := range keyspaces {
+		tables, err := db.InspectKeyspace(ctx, ks)
+		if err != nil {
+			return stats, fmt.Errorf("Dump(): %s: %w", ks, err)
+		}
+
+		err = enc.Encode(dumpRecord{Type: "keyspace", Keyspace: ks})
+		if err != nil {
+			return stats, fmt.Errorf("Dump(): %w", err)
+		}
+		stats.Keyspaces++
+
+		for _, t := range tables.Elements {
+			path := ks + ":" + elementString(t)
+			n, err := dumpTable(ctx, db, enc, path, opts.BatchSize)
+			stats.Entries += n
+			if err != nil {
+				return stats, fmt.Errorf("Dump(): %s: %w", path, err)
+			}
+			stats.Tables++
+		}
+	}
+
+	err = bw.Flush()
+	if err != nil {
+		return stats, fmt.Errorf("Dump(): %w", err)
+	}
+
+	return stats, nil
+}
+
+func dumpTable(ctx context.Context, db skytable.Skytable, enc *json.Encoder, path string, batchSize int) (entries int, err error) {
+	model, volatile, err := describeTable(ctx, db, path)
+	if err != nil {
+		return 0, err
+	}
+
+	err = enc.Encode(dumpRecord{Type: "table", Table: path, Model: model.String(), Volatile: volatile})
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := db.DBSize(ctx, path)
+	if err != nil || size == 0 {
+		return 0, err
+	}
+
+	arr, err := db.LSKeys(ctx, path, size)
+	if err != nil {
+		return 0, err
+	}
+	keys := make([]string, len(arr.Elements))
+	for i, k := range arr.Elements {
+		keys[i] = elementString(k)
+	}
+	sort.Strings(keys)
+
+	err = db.Use(ctx, path)
+	if err != nil {
+		return 0, err
+	}
+
+	if model.list {
+		for _, k := range keys {
+			l, err := db.LGet(ctx, k)
+			if errors.Is(err, protocol.ErrCodeNil) {
+				// Deleted meanwhile
+				continue
+			}
+			if err != nil {
+				return entries, err
+			}
+
+			elements := make([]dumpScalar, len(l.Elements))
+			for i, e := range l.Elements {
+				elements[i] = toDumpScalar(e)
+			}
+			key := dumpScalar(k)
+			err = enc.Encode(dumpRecord{Type: "entry", Table: path, Key: &key, List: elements})
+			if err != nil {
+				return entries, err
+			}
+			entries++
+		}
+		return entries, nil
+	}
+
+	for len(keys) > 0 {
+		batch := keys
+		if len(batch) > batchSize {
+			batch = batch[:batchSize]
+		}
+		keys = keys[len(batch):]
+
+		values, err := db.MGet(ctx, batch)
+		if err != nil {
+			return entries, err
+		}
+
+		for i, v := range values.Elements {
+			if v == nil {
+				// Deleted meanwhile
+				continue
+			}
+
+			key, value := dumpScalar(batch[i]), toDumpScalar(v)
+			err = enc.Encode(dumpRecord{Type: "entry", Table: path, Key: &key, Value: &value})
+			if err != nil {
+				return entries, err
+			}
+			entries++
+		}
+	}
+
+	return entries, nil
+}
+
+type RestoreOptions struct {
+	Overwrite bool // If true, existing keys are replaced, otherwise they are skipped
+	BatchSize int  // Entries written per MSET, 100 if 0
+}
+
+type RestoreStats struct {
+	Keyspaces int // Keyspaces created, existing ones excluded
+	Tables    int // Tables created, existing ones excluded
+	Entries   int // Entries written
+	Skipped   int // Entries not written as the keys exist
+}
+
+// Restore replays a dump written by [Dump], creating the missing keyspaces and tables
+// then writing the entries with MSET and LSET, after USE of each table: `db` is left on the last table restored.
+//
+// Existing tables are kept as they are, even if their model differs from the dumped one.
+func Restore(ctx context.Context, db skytable.Skytable, r io.Reader, opts RestoreOptions) (stats RestoreStats, err error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+
+	rs := &restorer{
+		ctx:    ctx,
+		db:     db,
+		opts:   opts,
+		models: make(map[string]keymapModel),
+	}
+
+	dec := json.NewDecoder(bufio.NewReader(r))
+	for line := 1; ; line++ {
+		var rec dumpRecord
+		err = dec.Decode(&rec)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return rs.stats, fmt.Errorf("Restore(): record #%d: %w", line, err)
+		}
+
+		err = rs.restore(rec)
+		if err != nil {
+			return rs.stats, fmt.Errorf("Restore(): record #%d: %w", line, err)
+		}
+	}
+
+	err = rs.flush()
+	if err != nil {
+		return rs.stats, fmt.Errorf("Restore(): %w", err)
+	}
+
+	return rs.stats, nil
+}
+
+type restorer struct {
+	ctx   context.Context
+	db    skytable.Skytable
+	opts  RestoreOptions
+	stats RestoreStats
+
+	models map[string]keymapModel // Models of the tables declared so far
+	table  string                 // The table in use
+	batch  []action.KVPair
+}
+
+func (rs *restorer) restore(rec dumpRecord) error {
+	switch rec.Type {
+	case "dump":
+		if rec.Version > DumpVersion {
+			return fmt.Errorf("unsupported dump version %d", rec.Version)
+		}
+	case "keyspace":
+		err := rs.db.CreateKeyspace(rs.ctx, rec.Keyspace)
+		if isErrStr(err, protocol.ErrStr_AlreadyExists) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", rec.Keyspace, err)
+		}
+		rs.stats.Keyspaces++
+	case "table":
+		model, valid := parseKeymapModel(rec.Model)
+		if !valid {
+			return fmt.Errorf("%s: unsupported model: %s", rec.Table, rec.Model)
+		}
+		rs.models[rec.Table] = model
+
+		created, err := createTable(rs.ctx, rs.db, rec.Table, model, rec.Volatile)
+		if err != nil {
+			return fmt.Errorf("%s: %w", rec.Table, err)
+		}
+		if created {
+			rs.stats.Tables++
+		}
+	case "entry":
+		return rs.restoreEntry(rec)
+	default:
+		return fmt.Errorf("unknown record type: %s", rec.Type)
+	}
+
+	return nil
+}
+
+func (rs *restorer) restoreEntry(rec dumpRecord) error {
+	model, declared := rs.models[rec.Table]
+	if !declared {
+		return fmt.Errorf("entry of undeclared table %s", rec.Table)
+	}
+	if rec.Key == nil {
+		return fmt.Errorf("%s: entry without key", rec.Table)
+	}
+
+	if rec.Table != rs.table {
+		err := rs.flush()
+		if err != nil {
+			return err
+		}
+		err = rs.db.Use(rs.ctx, rec.Table)
+		if err != nil {
+			return fmt.Errorf("%s: %w", rec.Table, err)
+		}
+		rs.table = rec.Table
+	}
+
+	key := string(*rec.Key)
+
+	if model.list {
+		var elements []any
+		for _, e := range rec.List {
+			elements = append(elements, e.as(model.valueType))
+		}
+		return rs.restoreList(key, elements)
+	}
+
+	if rec.Value == nil {
+		return fmt.Errorf("%s: entry without value", rec.Table)
+	}
+	rs.batch = append(rs.batch, action.KVPair{K: key, V: rec.Value.as(model.valueType)})
+	if len(rs.batch) >= rs.opts.BatchSize {
+		return rs.flush()
+	}
+
+	return nil
+}
+
+func (rs *restorer) restoreList(k string, elements []any) error {
+	err := rs.db.LSet(rs.ctx, k, elements)
+	if errors.Is(err, protocol.ErrCodeOverwriteError) {
+		if !rs.opts.Overwrite {
+			rs.stats.Skipped++
+			return nil
+		}
+
+		err = rs.db.LModClear(rs.ctx, k)
+		if err == nil && len(elements) > 0 {
+			err = rs.db.LModPush(rs.ctx, k, elements)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %s: %w", rs.table, k, err)
+	}
+
+	rs.stats.Entries++
+	return nil
+}
+
+// flush writes the pending entries.
+func (rs *restorer) flush() error {
+	if len(rs.batch) == 0 {
+		return nil
+	}
+
+	var set uint64
+	var err error
+	if rs.opts.Overwrite {
+		set, err = rs.db.USet(rs.ctx, rs.batch...)
+	} else {
+		set, err = rs.db.MSet(rs.ctx, rs.batch)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", rs.table, err)
+	}
+
+	rs.stats.Entries += int(set)
+	rs.stats.Skipped += len(rs.batch) - int(set)
+	rs.batch = rs.batch[:0]
+	return nil
+}
+
+type dumpRecord struct {
+	Type    string `json:"type"`
+	Version int    `json:"version,omitempty"`
+
+	Keyspace string `json:"keyspace,omitempty"`
+	Table    string `json:"table,omitempty"`
+	Model    string `json:"model,omitempty"`
+	Volatile bool   `json:"volatile,omitempty"`
+
+	Key   *dumpScalar  `json:"key,omitempty"`
+	Value *dumpScalar  `json:"value,omitempty"`
+	List  []dumpScalar `json:"list,omitempty"`
+}
+
+// dumpScalar is a key, a value or an element of a list,
+// encoded as a JSON string if it's valid UTF-8, otherwise as {"base64": "..."}.
+type dumpScalar []byte
+
+func toDumpScalar(v any) dumpScalar {
+	switch v := v.(type) {
+	case []byte:
+		return v
+	default:
+		return dumpScalar(elementString(v))
+	}
+}
+
+func (s dumpScalar) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(s) {
+		return json.Marshal(string(s))
+	}
+
+	return json.Marshal(struct {
+		Base64 []byte `json:"base64"`
+	}{s})
+}
+
+func (s *dumpScalar) UnmarshalJSON(b []byte) error {
+	var str string
+	if json.Unmarshal(b, &str) == nil {
+		*s = dumpScalar(str)
+		return nil
+	}
+
+	var bin struct {
+		Base64 []byte `json:"base64"`
+	}
+	err := json.Unmarshal(b, &bin)
+	if err != nil {
+		return err
+	}
+
+	*s = bin.Base64
+	return nil
+}
+
+// as converts the scalar to the Go type of the Skytable type.
+func (s dumpScalar) as(typ string) any {
+	if typ == protocol.DDLDataTypes_String.String() {
+		return string(s)
+	}
+	return []byte(s)
+}
+
+type keymapModel struct {
+	keyType   string
+	valueType string // The type of the elements if list
+	list      bool
+}
+
+func (m keymapModel) String() string {
+	if m.list {
+		return fmt.Sprintf("keymap(%s,list<%s>)", m.keyType, m.valueType)
+	}
+	return fmt.Sprintf("keymap(%s,%s)", m.keyType, m.valueType)
+}
+
+// parseKeymapModel parses a model like "keymap(str,list<binstr>)".
+func parseKeymapModel(s string) (keymapModel, bool) {
+	desc, err := protocol.ParseModel(s)
+	if err != nil {
+		return keymapModel{}, false
+	}
+
+	m, _, valid := keymapModelOf(desc)
+	return m, valid
+}
+
+// keymapModelOf returns the model and the volatility of keymaps with str/binstr keys, and str/binstr values or lists of them.
+func keymapModelOf(desc protocol.ModelDescription) (m keymapModel, volatile bool, valid bool) {
+	var key, value protocol.DDLType
+	switch d := desc.(type) {
+	case protocol.KeyMapDescription:
+		key, value, volatile = protocol.DDLTypeOf(d.KeyType), protocol.DDLTypeOf(d.ValueType), d.Volatile
+	case protocol.TypedKeyMapDescription:
+		key, value, volatile = d.KeyType, d.ValueType, d.Volatile
+	default:
+		return keymapModel{}, false, false
+	}
+
+	m.keyType = key.String()
+	switch {
+	case value.Name == protocol.DDLDataTypes_List.String() && len(value.Params) == 0:
+		// The elements of a plain "list" are binary strings
+		m.valueType, m.list = protocol.DDLDataTypes_BinaryString.String(), true
+	case value.Name == protocol.DDLDataTypes_List.String() && len(value.Params) == 1:
+		m.valueType, m.list = value.Params[0].String(), true
+	default:
+		m.valueType = value.String()
+	}
+
+	for _, t := range []string{m.keyType, m.valueType} {
+		if t != protocol.DDLDataTypes_String.String() && t != protocol.DDLDataTypes_BinaryString.String() {
+			return keymapModel{}, false, false
+		}
+	}
+	return m, volatile, true
+}
+
+// describeTable inspects the table.
+func describeTable(ctx context.Context, db skytable.Skytable, path string) (model keymapModel, volatile bool, err error) {
+	desc, err := db.InspectTable(ctx, path)
+	if err != nil {
+		return model, false, err
+	}
+
+	model, volatile, valid := keymapModelOf(desc)
+	if !valid {
+		return model, false, fmt.Errorf("unsupported table model: %s %s", desc.Model(), desc.Properties())
+	}
+
+	return model, volatile, nil
+}
+
+// createTable creates the table, returning false if it exists already.
+func createTable(ctx context.Context, db skytable.Skytable, path string, model keymapModel, volatile bool) (bool, error) {
+	segments := []any{"CREATE", "TABLE", path, model.String()}
+	if volatile {
+		segments = append(segments, "volatile")
+	}
+
+	resp, err := db.ExecSingleActionPacketRaw(segments...)
+	if err == nil {
+		err = resp.Err
+	}
+	if isErrStr(err, protocol.ErrStr_AlreadyExists) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if code, isCode := resp.Value.(protocol.ResponseCode); !isCode || code != protocol.RespOkay {
+		return false, fmt.Errorf("unexpected response: %v", resp.Value)
+	}
+	return true, nil
+}
+
+// listKeyspaces returns all the keyspaces but "system".
+func listKeyspaces(ctx context.Context, db skytable.Skytable) ([]string, error) {
+	arr, err := db.InspectKeyspaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var keyspaces []string
+	for _, e := range arr.Elements {
+		if ks := elementString(e); ks != "system" {
+			keyspaces = append(keyspaces, ks)
+		}
+	}
+	return keyspaces, nil
+}
+
+func isErrStr(err error, errStr string) bool {
+	var e *protocol.ErrorStringResponse
+	return errors.As(err, &e) && e.Errstr == errStr
+}
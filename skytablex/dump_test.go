@@ -0,0 +1,141 @@
+package skytablex
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/No3371/go-skytable/skytabletest"
+)
+
+// newTestDumpServer fills a keyspace "app" with a str table, a volatile binstr table and a list table.
+func newTestDumpServer(t *testing.T) *skytabletest.Server {
+	ctx := context.Background()
+	srv := newTestServer(t)
+	db := dial(t, srv, "")
+
+	if err := db.CreateKeyspace(ctx, "app"); err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range []string{"app:strs keymap(str,str)", "app:bins keymap(binstr,binstr) volatile", "app:lists keymap(str,list<str>)"} {
+		segments := []any{"CREATE", "TABLE"}
+		for _, s := range strings.Fields(table) {
+			segments = append(segments, s)
+		}
+		if resp, err := db.ExecSingleActionPacketRaw(segments...); err != nil || resp.Err != nil {
+			t.Fatal(err, resp.Err)
+		}
+	}
+
+	if err := dial(t, srv, "app:strs").Set(ctx, "k", "v"); err != nil {
+		t.Fatal(err)
+	}
+	bins := dial(t, srv, "app:bins")
+	for i := 0; i < 250; i++ {
+		if err := bins.Set(ctx, string([]byte{'k', byte(i)}), []byte{0xff, byte(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lists := dial(t, srv, "app:lists")
+	if err := lists.LSet(ctx, "l", []any{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := lists.LSet(ctx, "empty", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	return srv
+}
+
+func TestDumpRestore(t *testing.T) {
+	ctx := context.Background()
+
+	var dump bytes.Buffer
+	stats, err := Dump(ctx, dial(t, newTestDumpServer(t), ""), &dump, DumpOptions{Keyspaces: []string{"app"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats != (DumpStats{Keyspaces: 1, Tables: 3, Entries: 253}) {
+		t.Errorf("Dump() stats = %+v", stats)
+	}
+	for _, line := range []string{
+		`{"type":"table","table":"app:bins","model":"keymap(binstr,binstr)","volatile":true}`,
+		`{"type":"entry","table":"app:lists","key":"l","list":["a","b"]}`,
+		`{"type":"entry","table":"app:strs","key":"k","value":"v"}`,
+		`{"type":"entry","table":"app:bins","key":"k\u0000","value":{"base64":"/wA="}}`,
+	} {
+		if !strings.Contains(dump.String(), line+"\n") {
+			t.Errorf("dump does not contain %s:\n%s", line, dump.String()[:500])
+		}
+	}
+
+	srv := newTestServer(t)
+	restored, err := Restore(ctx, dial(t, srv, ""), bytes.NewReader(dump.Bytes()), RestoreOptions{BatchSize: 64})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored != (RestoreStats{Keyspaces: 1, Tables: 3, Entries: 253}) {
+		t.Errorf("Restore() stats = %+v", restored)
+	}
+
+	var again bytes.Buffer
+	if _, err := Dump(ctx, dial(t, srv, ""), &again, DumpOptions{Keyspaces: []string{"app"}}); err != nil {
+		t.Fatal(err)
+	}
+	if again.String() != dump.String() {
+		t.Errorf("dump of the restored keyspace differs:\n%s", again.String())
+	}
+
+	restored, err = Restore(ctx, dial(t, srv, ""), bytes.NewReader(dump.Bytes()), RestoreOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored != (RestoreStats{Skipped: 253}) {
+		t.Errorf("Restore() again stats = %+v, want all skipped", restored)
+	}
+}
+
+func TestRestore_Overwrite(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestDumpServer(t)
+
+	dump := `{"type":"dump","version":1}
+{"type":"keyspace","keyspace":"app"}
+{"type":"table","table":"app:strs","model":"keymap(str,str)"}
+{"type":"entry","table":"app:strs","key":"k","value":"new"}
+{"type":"table","table":"app:lists","model":"keymap(str,list<str>)"}
+{"type":"entry","table":"app:lists","key":"l","list":["c"]}
+`
+	stats, err := Restore(ctx, dial(t, srv, ""), strings.NewReader(dump), RestoreOptions{Overwrite: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats != (RestoreStats{Entries: 2}) {
+		t.Errorf("Restore() stats = %+v", stats)
+	}
+
+	if v, err := dial(t, srv, "app:strs").GetString(ctx, "k"); err != nil || v != "new" {
+		t.Errorf("GetString() = %s, %v, want new", v, err)
+	}
+	if l, err := dial(t, srv, "app:lists").LGet(ctx, "l"); err != nil || len(l.Elements) != 1 || l.Elements[0] != "c" {
+		t.Errorf("LGet() = %v, %v, want [c]", l, err)
+	}
+}
+
+func TestRestore_Invalid(t *testing.T) {
+	ctx := context.Background()
+	db := dial(t, newTestServer(t), "")
+
+	for _, dump := range []string{
+		`{"type":"dump","version":2}`,
+		`{"type":"nope"}`,
+		`{"type":"table","table":"default:t","model":"keymap(str,int)"}`,
+		`{"type":"entry","table":"default:strs","key":"k","value":"v"}`,
+		`not json`,
+	} {
+		if _, err := Restore(ctx, db, strings.NewReader(dump), RestoreOptions{}); err == nil {
+			t.Errorf("Restore(%s) succeeded, want an error", dump)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package skytablex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/protocol"
+)
+
+type ListIteratorOptions struct {
+	PageSize uint64 // Elements fetched per LGET RANGE, 100 if 0
+	// If true, the next page is fetched while the current one is consumed.
+	// The client must then be safe for concurrent use, like [skytable.ConnPool].
+	Prefetch bool
+}
+
+type listPage struct {
+	start    uint64
+	elements []any
+	err      error
+}
+
+// ListIterator walks a list page by page with LGET RANGE, fetching the pages lazily.
+//
+//	it := skytablex.NewListIterator(ctx, db, "list", skytablex.ListIteratorOptions{})
+//	defer it.Close()
+//	for it.Next() {
+//	    v := it.Value()
+//	}
+//	if it.Err() != nil { ... }
+//
+// Elements pushed while iterating are included. If elements are removed meanwhile,
+// some elements may be skipped or yielded twice.
+type ListIterator struct {
+	ctx  context.Context
+	db   skytable.Skytable
+	list string
+	opts ListIteratorOptions
+
+	length  uint64 // Last known length of the list
+	next    uint64 // Index of the first element of the next page, only used by fetch
+	start   uint64 // Index of the first element of the current page
+	page    []any
+	pos     int
+	pending chan listPage // The prefetched page
+	done    bool
+	err     error
+}
+
+// NewListIterator creates a ListIterator over the list of the current table of `db`.
+// A missing list is iterated as an empty list.
+func NewListIterator(ctx context.Context, db skytable.Skytable, list string, opts ListIteratorOptions) *ListIterator {
+	if opts.PageSize == 0 {
+		opts.PageSize = 100
+	}
+
+	return &ListIterator{
+		ctx:  ctx,
+		db:   db,
+		list: list,
+		opts: opts,
+		pos:  -1,
+	}
+}
+
+// Next advances to the next element, returning false when the list is exhausted or an error occurred.
+func (it *ListIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.pos++
+	for it.pos >= len(it.page) {
+		if it.done {
+			return false
+		}
+
+		var p listPage
+		if it.pending != nil {
+			p = <-it.pending
+			it.pending = nil
+		} else {
+			p = it.fetch()
+		}
+		if p.err != nil {
+			it.err = fmt.Errorf("ListIterator: %w", p.err)
+			return false
+		}
+
+		it.start, it.page, it.pos = p.start, p.elements, 0
+		if len(p.elements) == 0 {
+			it.done = true
+			return false
+		}
+
+		if it.opts.Prefetch {
+			it.pending = make(chan listPage, 1)
+			go func(pending chan listPage) {
+				pending <- it.fetch()
+			}(it.pending)
+		}
+	}
+
+	return true
+}
+
+// fetch reads the next page, advancing it.next. It's called by one goroutine at a time.
+func (it *ListIterator) fetch() listPage {
+	for refreshed := false; ; refreshed = true {
+		if it.next >= it.length || refreshed {
+			n, err := it.db.LGetLen(it.ctx, it.list)
+			if errors.Is(err, protocol.ErrCodeNil) {
+				return listPage{}
+			}
+			if err != nil {
+				return listPage{err: err}
+			}
+			it.length = n
+		}
+		if it.next >= it.length {
+			return listPage{}
+		}
+
+		to := it.next + it.opts.PageSize
+		if to > it.length {
+			to = it.length
+		}
+
+		arr, err := it.db.LGetRange(it.ctx, it.list, it.next, to)
+		if isEmptyList(err) && !refreshed {
+			// The list shrank
+			continue
+		}
+		if err != nil {
+			return listPage{err: err}
+		}
+
+		p := listPage{start: it.next, elements: arr.Elements}
+		it.next += uint64(len(arr.Elements))
+		return p
+	}
+}
+
+// Value returns the current element, a string or a []byte depending on the list type.
+func (it *ListIterator) Value() any {
+	if it.pos < 0 || it.pos >= len(it.page) {
+		return nil
+	}
+	return it.page[it.pos]
+}
+
+// Index returns the index of the current element in the list.
+func (it *ListIterator) Index() uint64 {
+	return it.start + uint64(it.pos)
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *ListIterator) Err() error {
+	return it.err
+}
+
+// Close stops the iteration, waiting for the prefetch in flight.
+func (it *ListIterator) Close() {
+	if it.pending != nil {
+		<-it.pending
+		it.pending = nil
+	}
+	it.done = true
+	it.page = nil
+}
+
+// TypedListIterator is a [ListIterator] decoding the elements into T,
+// which can be a string, a []byte, a bool or a numeric type.
+type TypedListIterator[T any] struct {
+	*ListIterator
+	value T
+}
+
+func NewTypedListIterator[T any](ctx context.Context, db skytable.Skytable, list string, opts ListIteratorOptions) *TypedListIterator[T] {
+	return &TypedListIterator[T]{
+		ListIterator: NewListIterator(ctx, db, list, opts),
+	}
+}
+
+// Next advances to the next element and decodes it, returning false when the list is exhausted or an error occurred.
+func (it *TypedListIterator[T]) Next() bool {
+	if !it.ListIterator.Next() {
+		return false
+	}
+
+	var v T
+	err := decodeScalar(reflect.ValueOf(&v).Elem(), it.ListIterator.Value())
+	if err != nil {
+		it.err = fmt.Errorf("ListIterator: element #%d: %w", it.Index(), err)
+		return false
+	}
+
+	it.value = v
+	return true
+}
+
+// Value returns the current element.
+func (it *TypedListIterator[T]) Value() T {
+	return it.value
+}
@@ -0,0 +1,37 @@
+//go:build go1.23
+
+package skytablex
+
+import "iter"
+
+// All returns an iterator over the remaining elements, check Err after the loop:
+//
+//	for v := range it.All() {
+//	}
+//	if it.Err() != nil { ... }
+//
+// Breaking out of the loop closes the ListIterator.
+func (it *ListIterator) All() iter.Seq[any] {
+	return func(yield func(any) bool) {
+		for it.Next() {
+			if !yield(it.Value()) {
+				it.Close()
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the remaining decoded elements, check Err after the loop.
+//
+// Breaking out of the loop closes the TypedListIterator.
+func (it *TypedListIterator[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for it.Next() {
+			if !yield(it.Value()) {
+				it.Close()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,25 @@
+//go:build go1.23
+
+package skytablex
+
+import "testing"
+
+func TestListIterator_All(t *testing.T) {
+	it := &TypedListIterator[int]{ListIterator: newTestList(t, 25)}
+	it.opts.Prefetch = true
+
+	var got []int
+	for v := range it.All() {
+		if v == 12 {
+			break
+		}
+		got = append(got, v)
+	}
+
+	if len(got) != 12 || it.Err() != nil {
+		t.Errorf("All() yielded %v, %v, want 0 to 11", got, it.Err())
+	}
+	if it.Next() {
+		t.Error("Next() = true after breaking out of All(), want false")
+	}
+}
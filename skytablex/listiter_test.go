@@ -0,0 +1,88 @@
+package skytablex
+
+import (
+	"context"
+	"strconv"
+	"testing"
+)
+
+func newTestList(t *testing.T, n int) *ListIterator {
+	ctx := context.Background()
+	db := dial(t, newTestServer(t), "default:lists")
+
+	elements := make([]any, n)
+	for i := range elements {
+		elements[i] = strconv.Itoa(i)
+	}
+	if err := db.LSet(ctx, "l", elements); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewListIterator(ctx, db, "l", ListIteratorOptions{PageSize: 10})
+}
+
+func TestListIterator(t *testing.T) {
+	for _, prefetch := range []bool{false, true} {
+		it := newTestList(t, 25)
+		it.opts.Prefetch = prefetch
+
+		i := 0
+		for it.Next() {
+			if got := string(it.Value().([]byte)); got != strconv.Itoa(i) {
+				t.Errorf("Value() = %s, want %d", got, i)
+			}
+			if it.Index() != uint64(i) {
+				t.Errorf("Index() = %d, want %d", it.Index(), i)
+			}
+			i++
+		}
+		it.Close()
+
+		if it.Err() != nil {
+			t.Fatal(it.Err())
+		}
+		if i != 25 {
+			t.Errorf("prefetch %v: iterated %d elements, want 25", prefetch, i)
+		}
+	}
+}
+
+func TestListIterator_Missing(t *testing.T) {
+	it := NewListIterator(context.Background(), dial(t, newTestServer(t), "default:lists"), "missing", ListIteratorOptions{})
+	defer it.Close()
+
+	if it.Next() {
+		t.Errorf("Next() = true, want false")
+	}
+	if it.Err() != nil {
+		t.Error(it.Err())
+	}
+}
+
+func TestTypedListIterator(t *testing.T) {
+	it := &TypedListIterator[int]{ListIterator: newTestList(t, 15)}
+	defer it.Close()
+
+	sum := 0
+	for it.Next() {
+		sum += it.Value()
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+	if sum != 105 {
+		t.Errorf("sum = %d, want 105", sum)
+	}
+
+	bools := &TypedListIterator[bool]{ListIterator: newTestList(t, 15)}
+	defer bools.Close()
+
+	if !bools.Next() || bools.Value() {
+		t.Fatalf("Next() = false or Value() = true, want 0 decoded as false")
+	}
+	for bools.Next() {
+	}
+	if bools.Err() == nil {
+		t.Error("Err() = nil, want an error decoding 2 as bool")
+	}
+}
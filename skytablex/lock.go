@@ -0,0 +1,287 @@
+package skytablex
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/action"
+	"github.com/No3371/go-skytable/protocol"
+)
+
+var (
+	// ErrLockHeld is returned by Lock when the lock is held by a lease which has not expired.
+	ErrLockHeld = errors.New("lock held by another owner")
+	// ErrLeaseLost is returned by Renew and Unlock when the lease expired and the lock was taken over.
+	ErrLeaseLost = errors.New("lease lost")
+)
+
+// Lease is a lock held until Expires.
+//
+// Token is the fencing token of the lease: it's greater than the tokens of all the previous leases of the lock,
+// so resources guarded by the lock can reject writes from holders not knowing their lease expired.
+type Lease struct {
+	Name    string
+	Owner   string
+	Token   uint64
+	Expires time.Time
+
+	db    skytable.Skytable
+	nonce string
+}
+
+type leaseValue struct {
+	expires time.Time
+	token   uint64
+	nonce   string
+	owner   string
+}
+
+// Stored as "expires|token|nonce|owner", expires being a UNIX timestamp in milliseconds
+func (v leaseValue) String() string {
+	return fmt.Sprintf("%d|%d|%s|%s", v.expires.UnixMilli(), v.token, v.nonce, v.owner)
+}
+
+func parseLeaseValue(v any) (leaseValue, error) {
+	var s string
+	switch v := v.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	}
+
+	parts := strings.SplitN(s, "|", 4)
+	if len(parts) != 4 {
+		return leaseValue{}, fmt.Errorf("invalid lease: %v", v)
+	}
+
+	ms, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return leaseValue{}, fmt.Errorf("invalid lease: %v", v)
+	}
+	token, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return leaseValue{}, fmt.Errorf("invalid lease: %v", v)
+	}
+
+	return leaseValue{
+		expires: time.UnixMilli(ms),
+		token:   token,
+		nonce:   parts[2],
+		owner:   parts[3],
+	}, nil
+}
+
+func (l *Lease) value() leaseValue {
+	return leaseValue{
+		expires: l.Expires,
+		token:   l.Token,
+		nonce:   l.nonce,
+		owner:   l.Owner,
+	}
+}
+
+// Lock tries to acquire the lock `name` for ttl, returning ErrLockHeld if it's held.
+//
+// The lock is the key `name`, acquired with SSET so only one owner can create it.
+// A lock whose lease expired, e.g. because its holder crashed, is taken over.
+// The fencing token counter is kept at name + "_fence", and takeovers use name + "_takeover_" + lease id keys.
+//
+// Skytable has no compare-and-swap, so every write of the lock (the fencing token assignment in Lock, Renew and Unlock)
+// reads the lock and verifies the lease right before writing it. A takeover landing between the check and the write,
+// one round trip apart, is still not detected: the write then overwrites or deletes the lease of the new holder.
+// Takeovers only happen once a lease expired, so renew well before it does.
+func Lock(ctx context.Context, db skytable.Skytable, name string, owner string, ttl time.Duration) (*Lease, error) {
+	nonce, err := newNonce()
+	if err != nil {
+		return nil, fmt.Errorf("Lock(): %w", err)
+	}
+
+	l := &Lease{
+		Name:    name,
+		Owner:   owner,
+		Expires: time.Now().Add(ttl),
+		db:      db,
+		nonce:   nonce,
+	}
+
+	err = db.SSet(ctx, []action.KVPair{{K: name, V: l.value().String()}})
+	if errors.Is(err, protocol.ErrCodeOverwriteError) {
+		err = l.takeOver(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Lock(): %w", err)
+	}
+
+	err = l.fence(ctx)
+	if errors.Is(err, ErrLeaseLost) {
+		err = ErrLockHeld
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Lock(): %w", err)
+	}
+
+	return l, nil
+}
+
+// takeOver replaces the lease holding the lock if it expired.
+func (l *Lease) takeOver(ctx context.Context) error {
+	held, err := l.read(ctx)
+	if errors.Is(err, protocol.ErrCodeNil) {
+		// Released meanwhile
+		return l.db.SSet(ctx, []action.KVPair{{K: l.Name, V: l.value().String()}})
+	}
+	if err != nil {
+		return err
+	}
+	if time.Now().Before(held.expires) {
+		return ErrLockHeld
+	}
+
+	// Only one contender can take over a given lease
+	marker := l.Name + "_takeover_" + held.nonce
+	err = l.db.SSet(ctx, []action.KVPair{{K: marker, V: l.Owner}})
+	if errors.Is(err, protocol.ErrCodeOverwriteError) {
+		return ErrLockHeld
+	}
+	if err != nil {
+		return err
+	}
+	defer l.db.Del(ctx, []string{marker})
+
+	current, err := l.read(ctx)
+	if err != nil && !errors.Is(err, protocol.ErrCodeNil) {
+		return err
+	}
+	if err != nil || current.nonce != held.nonce {
+		// Taken over or released since the first read
+		return ErrLockHeld
+	}
+
+	return l.db.Update(ctx, l.Name, l.value().String())
+}
+
+// fence assigns the next fencing token to the lease, which holds the lock.
+func (l *Lease) fence(ctx context.Context) error {
+	var last uint64
+	v, err := l.db.Get(ctx, l.Name+"_fence")
+	switch {
+	case errors.Is(err, protocol.ErrCodeNil):
+	case err != nil:
+		return err
+	default:
+		last, err = parseUintValue(v.Value)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The counter was read meanwhile, the lease may have been taken over
+	err = l.verify(ctx)
+	if err != nil {
+		return err
+	}
+
+	l.Token = last + 1
+	_, err = l.db.USet(ctx,
+		action.KVPair{K: l.Name + "_fence", V: strconv.FormatUint(l.Token, 10)},
+		action.KVPair{K: l.Name, V: l.value().String()},
+	)
+	return err
+}
+
+// Renew extends the lease to ttl from now.
+func (l *Lease) Renew(ctx context.Context, ttl time.Duration) error {
+	err := l.verify(ctx)
+	if err != nil {
+		return fmt.Errorf("Renew(): %w", err)
+	}
+
+	expires := time.Now().Add(ttl)
+	renewed := l.value()
+	renewed.expires = expires
+
+	err = l.db.Update(ctx, l.Name, renewed.String())
+	if err != nil {
+		return fmt.Errorf("Renew(): %w", err)
+	}
+
+	l.Expires = expires
+	return nil
+}
+
+// Unlock releases the lock if it's still held by the lease.
+func (l *Lease) Unlock(ctx context.Context) error {
+	err := l.verify(ctx)
+	if err != nil {
+		return fmt.Errorf("Unlock(): %w", err)
+	}
+
+	err = l.db.SDel(ctx, []string{l.Name})
+	if errors.Is(err, protocol.ErrCodeNil) {
+		return fmt.Errorf("Unlock(): %w", ErrLeaseLost)
+	}
+	if err != nil {
+		return fmt.Errorf("Unlock(): %w", err)
+	}
+
+	return nil
+}
+
+// verify returns ErrLeaseLost if the lock is not held by the lease anymore.
+func (l *Lease) verify(ctx context.Context) error {
+	held, err := l.read(ctx)
+	if errors.Is(err, protocol.ErrCodeNil) {
+		return ErrLeaseLost
+	}
+	if err != nil {
+		return err
+	}
+	if held.nonce != l.nonce {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+func (l *Lease) read(ctx context.Context) (leaseValue, error) {
+	v, err := l.db.Get(ctx, l.Name)
+	if err != nil {
+		return leaseValue{}, err
+	}
+
+	return parseLeaseValue(v.Value)
+}
+
+func newNonce() (string, error) {
+	b := make([]byte, 8)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func parseUintValue(v any) (uint64, error) {
+	var s string
+	switch v := v.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	}
+
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid counter: %v", v)
+	}
+	return n, nil
+}
@@ -0,0 +1,114 @@
+package skytablex
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLock(t *testing.T) {
+	ctx := context.Background()
+	db := dial(t, newTestServer(t), "default:default")
+
+	l, err := Lock(ctx, db, "cron", "a", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Token != 1 {
+		t.Errorf("Token = %d, want 1", l.Token)
+	}
+
+	if _, err := Lock(ctx, db, "cron", "b", time.Minute); !errors.Is(err, ErrLockHeld) {
+		t.Fatalf("Lock() error = %v, want %v", err, ErrLockHeld)
+	}
+
+	if err := l.Renew(ctx, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if time.Until(l.Expires) <= time.Minute {
+		t.Errorf("Expires = %s, want about an hour from now", l.Expires)
+	}
+
+	if err := l.Unlock(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Unlock(ctx); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("Unlock() error = %v, want %v", err, ErrLeaseLost)
+	}
+
+	l, err = Lock(ctx, db, "cron", "b", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Token != 2 {
+		t.Errorf("Token = %d, want 2", l.Token)
+	}
+}
+
+func TestLock_Expired(t *testing.T) {
+	ctx := context.Background()
+	db := dial(t, newTestServer(t), "default:default")
+
+	crashed, err := Lock(ctx, db, "cron", "a", 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	l, err := Lock(ctx, db, "cron", "b", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Token <= crashed.Token {
+		t.Errorf("Token = %d, want more than %d", l.Token, crashed.Token)
+	}
+
+	if err := crashed.Renew(ctx, time.Minute); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("Renew() error = %v, want %v", err, ErrLeaseLost)
+	}
+	if err := crashed.Unlock(ctx); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("Unlock() error = %v, want %v", err, ErrLeaseLost)
+	}
+	if err := l.Unlock(ctx); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLock_Contended(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestServer(t)
+
+	// Contenders racing for an expired lease
+	if _, err := Lock(ctx, dial(t, srv, "default:default"), "cron", "crashed", time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	var mu sync.Mutex
+	var leases []*Lease
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		db := dial(t, srv, "default:default")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l, err := Lock(ctx, db, "cron", "contender", time.Minute)
+			if err != nil && !errors.Is(err, ErrLockHeld) {
+				t.Error(err)
+			}
+			if l != nil {
+				mu.Lock()
+				leases = append(leases, l)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(leases) != 1 {
+		t.Fatalf("got %d leases, want 1", len(leases))
+	}
+}
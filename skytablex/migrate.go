@@ -0,0 +1,503 @@
+package skytablex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/action"
+	"github.com/No3371/go-skytable/protocol"
+)
+
+// Migration is a versioned data transformation, applied once per keyspace by a [Migrator].
+type Migration struct {
+	Version uint64 // Unique and greater than 0, migrations are applied in ascending order
+	Name    string
+
+	// Up transforms the data, it may USE any table, see [TransformTable] and [MoveKeys].
+	// A migration failing, or whose lease of the lock is lost, is not recorded and runs again on the next Migrate:
+	// Up should be safe to re-run over partially migrated data.
+	Up func(ctx context.Context, db skytable.Skytable) error
+}
+
+// AppliedMigration is a migration recorded in the migrations table.
+type AppliedMigration struct {
+	Version uint64
+	Name    string
+	At      time.Time
+}
+
+type MigratorOptions struct {
+	Table   string        // The table of the keyspace recording the applied versions and holding the lock, "skytablex_migrations" if ""
+	Owner   string        // The owner of the lock, "hostname:pid" if ""
+	LockTTL time.Duration // The lease of the lock, renewed around every migration, 10 minutes if 0
+}
+
+// Migrator applies the pending migrations of a keyspace.
+//
+// The applied versions are recorded in a keymap(str,str) table of the keyspace, created on the first Migrate,
+// which also holds the [Lock] ensuring only one Migrator runs on the keyspace at a time.
+// Versions missing below the last applied one, like those merged from another branch, are applied too.
+type Migrator struct {
+	db         skytable.Skytable
+	keyspace   string
+	table      string
+	migrations []Migration
+	opts       MigratorOptions
+}
+
+// NewMigrator creates a Migrator applying the migrations to the keyspace through db.
+// The migrations are sorted by version, the slice is not modified.
+func NewMigrator(db skytable.Skytable, keyspace string, migrations []Migration, opts MigratorOptions) *Migrator {
+	if opts.Table == "" {
+		opts.Table = "skytablex_migrations"
+	}
+	if opts.Owner == "" {
+		host, _ := os.Hostname()
+		opts.Owner = fmt.Sprintf("%s:%d", host, os.Getpid())
+	}
+	if opts.LockTTL <= 0 {
+		opts.LockTTL = 10 * time.Minute
+	}
+
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	return &Migrator{
+		db:         db,
+		keyspace:   keyspace,
+		table:      keyspace + ":" + opts.Table,
+		migrations: sorted,
+		opts:       opts,
+	}
+}
+
+const (
+	migrationLockKey       = "lock"
+	migrationVersionPrefix = "version_"
+)
+
+// Zero padded so the keys sort by version
+func migrationVersionKey(v uint64) string {
+	return fmt.Sprintf("%s%020d", migrationVersionPrefix, v)
+}
+
+// Stored as "at|name", at being a UNIX timestamp in milliseconds
+func (a AppliedMigration) value() string {
+	return fmt.Sprintf("%d|%s", a.At.UnixMilli(), a.Name)
+}
+
+func parseAppliedMigration(key string, v any) (AppliedMigration, error) {
+	version, err := strconv.ParseUint(strings.TrimPrefix(key, migrationVersionPrefix), 10, 64)
+	if err != nil {
+		return AppliedMigration{}, fmt.Errorf("invalid migration record: %s", key)
+	}
+
+	at, name, found := strings.Cut(elementString(v), "|")
+	ms, err := strconv.ParseInt(at, 10, 64)
+	if !found || err != nil {
+		return AppliedMigration{}, fmt.Errorf("invalid migration record: %s: %v", key, v)
+	}
+
+	return AppliedMigration{
+		Version: version,
+		Name:    name,
+		At:      time.UnixMilli(ms),
+	}, nil
+}
+
+// Applied returns the recorded migrations, by ascending version.
+func (m *Migrator) Applied(ctx context.Context) ([]AppliedMigration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("*Migrator.Applied(): %w", err)
+	}
+	return applied, nil
+}
+
+// Pending returns the migrations not recorded yet, by ascending version.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	err := m.validate()
+	if err != nil {
+		return nil, fmt.Errorf("*Migrator.Pending(): %w", err)
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("*Migrator.Pending(): %w", err)
+	}
+	return m.pending(applied), nil
+}
+
+// Migrate acquires the lock of the keyspace and applies the pending migrations in order,
+// returning the applied ones. ErrLockHeld is returned if another Migrator holds the lock.
+//
+// The client is left on the migrations table.
+func (m *Migrator) Migrate(ctx context.Context) (migrated []Migration, err error) {
+	err = m.validate()
+	if err != nil {
+		return nil, fmt.Errorf("*Migrator.Migrate(): %w", err)
+	}
+
+	err = m.ensureTable(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("*Migrator.Migrate(): %s: %w", m.table, err)
+	}
+
+	err = m.db.Use(ctx, m.table)
+	if err != nil {
+		return nil, fmt.Errorf("*Migrator.Migrate(): %w", err)
+	}
+	lease, err := Lock(ctx, m.db, migrationLockKey, m.opts.Owner, m.opts.LockTTL)
+	if err != nil {
+		return nil, fmt.Errorf("*Migrator.Migrate(): %s: %w", m.keyspace, err)
+	}
+	defer func() {
+		unlockErr := m.db.Use(ctx, m.table)
+		if unlockErr == nil {
+			unlockErr = lease.Unlock(ctx)
+		}
+		if err == nil && unlockErr != nil {
+			err = fmt.Errorf("*Migrator.Migrate(): %w", unlockErr)
+		}
+	}()
+
+	// Read under the lock, as another Migrator may have applied migrations meanwhile
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("*Migrator.Migrate(): %w", err)
+	}
+
+	for _, mig := range m.pending(applied) {
+		err = m.apply(ctx, lease, mig)
+		if err != nil {
+			return migrated, fmt.Errorf("*Migrator.Migrate(): %d %s: %w", mig.Version, mig.Name, err)
+		}
+		migrated = append(migrated, mig)
+	}
+
+	return migrated, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, lease *Lease, mig Migration) error {
+	err := lease.Renew(ctx, m.opts.LockTTL)
+	if err != nil {
+		return err
+	}
+
+	err = mig.Up(ctx, m.db)
+	if err != nil {
+		return err
+	}
+
+	err = m.db.Use(ctx, m.table)
+	if err != nil {
+		return err
+	}
+	// Not recorded if the lock was taken over while migrating, the new holder runs it again
+	err = lease.Renew(ctx, m.opts.LockTTL)
+	if err != nil {
+		return err
+	}
+
+	record := AppliedMigration{Version: mig.Version, Name: mig.Name, At: time.Now()}
+	return m.db.SSet(ctx, []action.KVPair{{K: migrationVersionKey(mig.Version), V: record.value()}})
+}
+
+func (m *Migrator) validate() error {
+	for i, mig := range m.migrations {
+		if mig.Version == 0 {
+			return fmt.Errorf("migration %q: version 0", mig.Name)
+		}
+		if i > 0 && m.migrations[i-1].Version == mig.Version {
+			return fmt.Errorf("migration %q: duplicated version %d", mig.Name, mig.Version)
+		}
+		if mig.Up == nil {
+			return fmt.Errorf("migration %d %s: no Up", mig.Version, mig.Name)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) pending(applied []AppliedMigration) []Migration {
+	done := make(map[uint64]bool, len(applied))
+	for _, a := range applied {
+		done[a.Version] = true
+	}
+
+	var pending []Migration
+	for _, mig := range m.migrations {
+		if !done[mig.Version] {
+			pending = append(pending, mig)
+		}
+	}
+	return pending
+}
+
+// ensureTable creates the keyspace and the migrations table if they don't exist.
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	err := m.db.CreateKeyspace(ctx, m.keyspace)
+	if err != nil && !isErrStr(err, protocol.ErrStr_AlreadyExists) {
+		return err
+	}
+
+	err = m.db.CreateTable(ctx, m.table, protocol.KeyMapDescription{
+		KeyType:   protocol.DDLDataTypes_String,
+		ValueType: protocol.DDLDataTypes_String,
+	})
+	if err != nil && !isErrStr(err, protocol.ErrStr_AlreadyExists) {
+		return err
+	}
+	return nil
+}
+
+// applied reads the migrations table, which may not exist yet.
+func (m *Migrator) applied(ctx context.Context) ([]AppliedMigration, error) {
+	size, err := m.db.DBSize(ctx, m.table)
+	if isErrStr(err, protocol.ErrStr_ContainerNotFound) {
+		return nil, nil
+	}
+	if err != nil || size == 0 {
+		return nil, err
+	}
+
+	arr, err := m.db.LSKeys(ctx, m.table, size)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, e := range arr.Elements {
+		if k := elementString(e); strings.HasPrefix(k, migrationVersionPrefix) {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	err = m.db.Use(ctx, m.table)
+	if err != nil {
+		return nil, err
+	}
+	values, err := m.db.MGet(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make([]AppliedMigration, 0, len(keys))
+	for i, v := range values.Elements {
+		if v == nil {
+			continue
+		}
+		a, err := parseAppliedMigration(keys[i], v)
+		if err != nil {
+			return nil, err
+		}
+		applied = append(applied, a)
+	}
+
+	sort.Slice(applied, func(i, j int) bool { return applied[i].Version < applied[j].Version })
+	return applied, nil
+}
+
+// TransformTable rewrites the values of the table in batches of batchSize keys (100 if 0), with MGET then USET.
+// fn returns the new value and whether it changed, unchanged values are not written.
+//
+// The keys are listed once with LSKEYS, keys added meanwhile are not transformed. The client is left on the table.
+func TransformTable(ctx context.Context, db skytable.Skytable, table string, batchSize int, fn func(key string, value any) (any, bool, error)) (updated int, err error) {
+	err = forEachBatch(ctx, db, table, batchSize, func(keys []string) error {
+		err := db.Use(ctx, table)
+		if err != nil {
+			return err
+		}
+		values, err := db.MGet(ctx, keys)
+		if err != nil {
+			return err
+		}
+
+		var entries []action.KVPair
+		for i, v := range values.Elements {
+			if v == nil {
+				// Deleted meanwhile
+				continue
+			}
+			nv, changed, err := fn(keys[i], v)
+			if err != nil {
+				return fmt.Errorf("%s: %w", keys[i], err)
+			}
+			if changed {
+				entries = append(entries, action.KVPair{K: keys[i], V: nv})
+			}
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+
+		_, err = db.USet(ctx, entries...)
+		if err != nil {
+			return err
+		}
+		updated += len(entries)
+		return nil
+	})
+	if err != nil {
+		return updated, fmt.Errorf("TransformTable(): %s: %w", table, err)
+	}
+
+	return updated, nil
+}
+
+// ErrMoveConflict is returned by MoveKeys when keys of src already exist in dst with other values.
+var ErrMoveConflict = errors.New("keys exist in the destination")
+
+type MoveOptions struct {
+	BatchSize int  // Keys moved per batch, 100 if 0
+	Overwrite bool // If true, entries of dst are replaced with USET, otherwise they are kept and reported
+}
+
+// MoveKeys moves the entries of the table src to the table dst in batches of keys,
+// with MGET, MSET (or USET if opts.Overwrite) then DEL. fn, if not nil, returns the new key and value of each entry,
+// e.g. to convert str values to binstr for a keymap(str,binstr) dst.
+//
+// MSET never overwrites: when a batch is not entirely set, dst is read back and only the entries it holds
+// with the moved values are deleted from src, so an interrupted move can be run again. The other entries are left
+// in src and, once everything else is moved, ErrMoveConflict is returned listing them. The client is left on src.
+func MoveKeys(ctx context.Context, db skytable.Skytable, src, dst string, opts MoveOptions, fn func(key string, value any) (string, any, error)) (moved int, err error) {
+	var conflicts []string
+	err = forEachBatch(ctx, db, src, opts.BatchSize, func(keys []string) error {
+		err := db.Use(ctx, src)
+		if err != nil {
+			return err
+		}
+		values, err := db.MGet(ctx, keys)
+		if err != nil {
+			return err
+		}
+
+		var (
+			entries []action.KVPair
+			copied  []string
+		)
+		for i, v := range values.Elements {
+			if v == nil {
+				// Deleted meanwhile
+				continue
+			}
+			k := keys[i]
+			if fn != nil {
+				k, v, err = fn(k, v)
+				if err != nil {
+					return fmt.Errorf("%s: %w", keys[i], err)
+				}
+			}
+			entries = append(entries, action.KVPair{K: k, V: v})
+			copied = append(copied, keys[i])
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+
+		err = db.Use(ctx, dst)
+		if err != nil {
+			return err
+		}
+		if opts.Overwrite {
+			_, err = db.USet(ctx, entries...)
+			if err != nil {
+				return err
+			}
+		} else {
+			set, err := db.MSet(ctx, entries)
+			if err != nil {
+				return err
+			}
+			if int(set) < len(entries) {
+				copied, err = movedKeys(ctx, db, entries, copied, &conflicts)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		moved += len(copied)
+
+		err = db.Use(ctx, src)
+		if err != nil {
+			return err
+		}
+		if len(copied) == 0 {
+			return nil
+		}
+		_, err = db.Del(ctx, copied)
+		return err
+	})
+	if err != nil {
+		return moved, fmt.Errorf("MoveKeys(): %s to %s: %w", src, dst, err)
+	}
+	if len(conflicts) > 0 {
+		return moved, fmt.Errorf("MoveKeys(): %s to %s: %w: %s", src, dst, ErrMoveConflict, strings.Join(conflicts, ", "))
+	}
+
+	return moved, nil
+}
+
+// movedKeys reads back the entries of a partially set batch from the current table, returning the source keys
+// of those holding the moved values. The source keys of the others are appended to conflicts.
+func movedKeys(ctx context.Context, db skytable.Skytable, entries []action.KVPair, sources []string, conflicts *[]string) ([]string, error) {
+	keys := make([]string, len(entries))
+	for i, e := range entries {
+		keys[i] = e.K
+	}
+	values, err := db.MGet(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	if len(values.Elements) != len(entries) {
+		return nil, fmt.Errorf("MGET returned %d values for %d keys", len(values.Elements), len(entries))
+	}
+
+	var moved []string
+	for i, v := range values.Elements {
+		if v != nil && elementString(v) == elementString(entries[i].V) {
+			moved = append(moved, sources[i])
+		} else {
+			*conflicts = append(*conflicts, sources[i])
+		}
+	}
+	return moved, nil
+}
+
+// forEachBatch lists the keys of the table and calls fn with batches of them.
+func forEachBatch(ctx context.Context, db skytable.Skytable, table string, batchSize int, fn func(keys []string) error) error {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	it := ScanKeys(ctx, db, ScanOptions{Tables: []string{table}})
+	defer it.Close()
+
+	batch := make([]string, 0, batchSize)
+	for it.Next() {
+		batch = append(batch, it.Key())
+		if len(batch) == batchSize {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if it.Err() != nil {
+		return it.Err()
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+
+	return nil
+}
@@ -0,0 +1,205 @@
+package skytablex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/action"
+	"github.com/No3371/go-skytable/protocol"
+)
+
+var testMigrations = []Migration{
+	{Version: 1, Name: "create users", Up: func(ctx context.Context, db skytable.Skytable) error {
+		err := db.CreateTable(ctx, "app:users", protocol.KeyMapDescription{KeyType: protocol.DDLDataTypes_String, ValueType: protocol.DDLDataTypes_String})
+		if err != nil && !isErrStr(err, protocol.ErrStr_AlreadyExists) {
+			return err
+		}
+		if err := db.Use(ctx, "app:users"); err != nil {
+			return err
+		}
+		for i := 0; i < 25; i++ {
+			if _, err := db.USet(ctx, action.KVPair{K: fmt.Sprintf("user-%02d", i), V: "name"}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}},
+	{Version: 2, Name: "upper names", Up: func(ctx context.Context, db skytable.Skytable) error {
+		_, err := TransformTable(ctx, db, "app:users", 10, func(key string, value any) (any, bool, error) {
+			s := elementString(value)
+			return strings.ToUpper(s), s != strings.ToUpper(s), nil
+		})
+		return err
+	}},
+	{Version: 3, Name: "users to binstr", Up: func(ctx context.Context, db skytable.Skytable) error {
+		err := db.CreateTable(ctx, "app:users_bin", protocol.KeyMapDescription{KeyType: protocol.DDLDataTypes_String, ValueType: protocol.DDLDataTypes_BinaryString})
+		if err != nil && !isErrStr(err, protocol.ErrStr_AlreadyExists) {
+			return err
+		}
+		_, err = MoveKeys(ctx, db, "app:users", "app:users_bin", MoveOptions{BatchSize: 10}, func(key string, value any) (string, any, error) {
+			return key, []byte(elementString(value)), nil
+		})
+		return err
+	}},
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	db := dial(t, newTestServer(t), "")
+
+	m := NewMigrator(db, "app", []Migration{testMigrations[2], testMigrations[0]}, MigratorOptions{})
+	migrated, err := m.Migrate(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrated) != 2 || migrated[0].Version != 1 || migrated[1].Version != 3 {
+		t.Fatalf("migrated %v, want versions 1 and 3", migrated)
+	}
+
+	// Version 2 merged later, applied although 3 already is
+	m = NewMigrator(db, "app", testMigrations, MigratorOptions{})
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].Version != 2 {
+		t.Fatalf("pending %v, want version 2", pending)
+	}
+	if _, err := m.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 3 {
+		t.Fatalf("applied %v, want 3 migrations", applied)
+	}
+	for i, a := range applied {
+		if a.Version != uint64(i+1) || a.Name != testMigrations[i].Name || time.Since(a.At) > time.Minute {
+			t.Errorf("applied[%d] = %+v", i, a)
+		}
+	}
+
+	migrated, err = m.Migrate(ctx)
+	if err != nil || len(migrated) != 0 {
+		t.Fatalf("Migrate() = %v, %v, want nothing to migrate", migrated, err)
+	}
+
+	if size, err := db.DBSize(ctx, "app:users"); err != nil || size != 0 {
+		t.Errorf("app:users has %d keys (%v), want 0", size, err)
+	}
+	if err := db.Use(ctx, "app:users_bin"); err != nil {
+		t.Fatal(err)
+	}
+	v, err := db.GetBytes(ctx, "user-07")
+	if err != nil || string(v) != "name" {
+		t.Errorf("user-07 = %q, %v, want %q", v, err, "name")
+	}
+}
+
+func TestMigrator_Failure(t *testing.T) {
+	ctx := context.Background()
+	db := dial(t, newTestServer(t), "")
+
+	failing := errors.New("failing")
+	migrations := []Migration{
+		testMigrations[0],
+		{Version: 2, Name: "failing", Up: func(ctx context.Context, db skytable.Skytable) error { return failing }},
+	}
+
+	migrated, err := NewMigrator(db, "app", migrations, MigratorOptions{}).Migrate(ctx)
+	if !errors.Is(err, failing) {
+		t.Fatalf("Migrate() error = %v, want %v", err, failing)
+	}
+	if len(migrated) != 1 {
+		t.Fatalf("migrated %v, want version 1", migrated)
+	}
+
+	migrations[1] = testMigrations[1]
+	m := NewMigrator(db, "app", migrations, MigratorOptions{})
+	migrated, err = m.Migrate(ctx)
+	if err != nil || len(migrated) != 1 || migrated[0].Version != 2 {
+		t.Fatalf("Migrate() = %v, %v, want version 2", migrated, err)
+	}
+
+	if _, err := NewMigrator(db, "app", append(migrations, testMigrations[1]), MigratorOptions{}).Migrate(ctx); err == nil {
+		t.Error("expecting an error on duplicated versions")
+	}
+}
+
+func TestMigrator_Locked(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestServer(t)
+	db := dial(t, srv, "")
+
+	m := NewMigrator(db, "app", testMigrations[:1], MigratorOptions{Owner: "a"})
+	if _, err := m.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	other := dial(t, srv, "app:skytablex_migrations")
+	l, err := Lock(ctx, other, "lock", "b", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m = NewMigrator(db, "app", testMigrations, MigratorOptions{Owner: "a"})
+	if _, err := m.Migrate(ctx); !errors.Is(err, ErrLockHeld) {
+		t.Fatalf("Migrate() error = %v, want %v", err, ErrLockHeld)
+	}
+
+	if err := l.Unlock(ctx); err != nil {
+		t.Fatal(err)
+	}
+	migrated, err := m.Migrate(ctx)
+	if err != nil || len(migrated) != 2 {
+		t.Fatalf("Migrate() = %v, %v, want versions 2 and 3", migrated, err)
+	}
+}
+
+func TestMoveKeys(t *testing.T) {
+	ctx := context.Background()
+	db := dial(t, newTestServer(t), "default:default")
+
+	// k2 was moved by an interrupted run, k3 exists with another value
+	if _, err := db.USet(ctx, action.KVPair{K: "k2", V: "v2"}, action.KVPair{K: "k3", V: "other"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Use(ctx, "default:strs"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.USet(ctx, action.KVPair{K: "k1", V: "v1"}, action.KVPair{K: "k2", V: "v2"}, action.KVPair{K: "k3", V: "v3"}); err != nil {
+		t.Fatal(err)
+	}
+
+	moved, err := MoveKeys(ctx, db, "default:strs", "default:default", MoveOptions{}, nil)
+	if !errors.Is(err, ErrMoveConflict) || !strings.Contains(err.Error(), "k3") {
+		t.Fatalf("MoveKeys() error = %v, want %v for k3", err, ErrMoveConflict)
+	}
+	if moved != 2 {
+		t.Errorf("MoveKeys() moved %d, want 2", moved)
+	}
+	if n, err := db.Exists(ctx, []string{"k1", "k2", "k3"}); err != nil || n != 1 {
+		t.Fatalf("Exists() = %d, %v, want only k3 left in src", n, err)
+	}
+
+	moved, err = MoveKeys(ctx, db, "default:strs", "default:default", MoveOptions{Overwrite: true}, nil)
+	if err != nil || moved != 1 {
+		t.Fatalf("MoveKeys() = %d, %v, want 1 moved", moved, err)
+	}
+	if err := db.Use(ctx, "default:default"); err != nil {
+		t.Fatal(err)
+	}
+	for k, want := range map[string]string{"k1": "v1", "k2": "v2", "k3": "v3"} {
+		if v, err := db.GetBytes(ctx, k); err != nil || string(v) != want {
+			t.Errorf("%s = %q, %v, want %q", k, v, err, want)
+		}
+	}
+}
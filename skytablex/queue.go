@@ -0,0 +1,342 @@
+package skytablex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/protocol"
+)
+
+// ErrQueueEmpty is returned by Dequeue, Peek and Receive when the queue is empty.
+var ErrQueueEmpty = errors.New("queue is empty")
+
+type QueueOptions struct {
+	VisibilityTimeout time.Duration // How long a received message is hidden before it's delivered again, 30 seconds if 0
+	MinPoll           time.Duration // The first delay of the blocking methods when the queue is empty, 10ms if 0
+	MaxPoll           time.Duration // The delay doubles up to MaxPoll, 1 second if 0
+	RequeueInterval   time.Duration // How often expired messages are requeued in background, never if 0
+	OnRequeueError    func(error)   // Called when requeuing in background fails, ignored if nil
+}
+
+// Queue is a FIFO queue kept in the list `name` of a keymap(str,list) table.
+//
+// Dequeue pops the first element, at most once: a consumer crashing before processing it loses it.
+// Receive and Ack provide at-least-once delivery: a received message is recorded in the list name + "_processing:" + id,
+// and its id in the list name + "_processing", until it's acknowledged. Messages not acknowledged within
+// the VisibilityTimeout are pushed back to the queue by RequeueExpired.
+// A consumer crashing between the pop and the record of a message still loses it.
+//
+// Use clients safe for concurrent use like [skytable.ConnPool] with RequeueInterval.
+type Queue struct {
+	db   skytable.Skytable
+	name string
+	opts QueueOptions
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewQueue creates a Queue over the list `name` of the current table of `db`, which must have a list value type.
+func NewQueue(db skytable.Skytable, name string, opts QueueOptions) *Queue {
+	if opts.VisibilityTimeout <= 0 {
+		opts.VisibilityTimeout = 30 * time.Second
+	}
+	if opts.MinPoll <= 0 {
+		opts.MinPoll = 10 * time.Millisecond
+	}
+	if opts.MaxPoll <= 0 {
+		opts.MaxPoll = time.Second
+	}
+
+	q := &Queue{
+		db:   db,
+		name: name,
+		opts: opts,
+		stop: make(chan struct{}),
+	}
+
+	if opts.RequeueInterval > 0 {
+		q.wg.Add(1)
+		go q.requeuer()
+	}
+
+	return q
+}
+
+// Close stops requeuing in background. It can be called more than once.
+func (q *Queue) Close() {
+	q.stopOnce.Do(func() { close(q.stop) })
+	q.wg.Wait()
+}
+
+// Enqueue appends the elements to the queue.
+func (q *Queue) Enqueue(ctx context.Context, elements ...any) error {
+	err := push(ctx, q.db, q.name, elements)
+	if err != nil {
+		return fmt.Errorf("Enqueue(): %w", err)
+	}
+
+	return nil
+}
+
+// push appends the elements to the list, creating it if needed. It's shared with [Expiry].
+func push(ctx context.Context, db skytable.Skytable, list string, elements []any) error {
+	for {
+		err := db.LModPush(ctx, list, elements)
+		if !errors.Is(err, protocol.ErrCodeNil) {
+			return err
+		}
+
+		// The list does not exist yet
+		err = db.LSet(ctx, list, elements)
+		if !errors.Is(err, protocol.ErrCodeOverwriteError) {
+			return err
+		}
+	}
+}
+
+// Dequeue pops the first element of the queue, or returns ErrQueueEmpty.
+func (q *Queue) Dequeue(ctx context.Context) (any, error) {
+	resp, err := q.db.LModPopIndex(ctx, q.name, 0)
+	if isEmptyList(err) {
+		return nil, ErrQueueEmpty
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Dequeue(): %w", err)
+	}
+
+	return resp.Value, nil
+}
+
+// DequeueWait pops the first element of the queue, polling until there is one or ctx is done.
+func (q *Queue) DequeueWait(ctx context.Context) (any, error) {
+	return wait(ctx, q, q.Dequeue)
+}
+
+// Peek returns the first element of the queue without removing it, or ErrQueueEmpty.
+func (q *Queue) Peek(ctx context.Context) (any, error) {
+	resp, err := q.db.LGetFirst(ctx, q.name)
+	if isEmptyList(err) {
+		return nil, ErrQueueEmpty
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Peek(): %w", err)
+	}
+
+	return resp.Value, nil
+}
+
+// Len returns the count of elements in the queue, received messages excluded.
+func (q *Queue) Len(ctx context.Context) (uint64, error) {
+	n, err := q.db.LGetLen(ctx, q.name)
+	if errors.Is(err, protocol.ErrCodeNil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("Len(): %w", err)
+	}
+
+	return n, nil
+}
+
+// Message is an element received from a [Queue], delivered again after Deadline unless acknowledged.
+type Message struct {
+	ID       string
+	Value    any
+	Deadline time.Time
+
+	q *Queue
+}
+
+// Receive pops the first element of the queue and records it until it's acknowledged, or returns ErrQueueEmpty.
+func (q *Queue) Receive(ctx context.Context) (*Message, error) {
+	resp, err := q.db.LModPopIndex(ctx, q.name, 0)
+	if isEmptyList(err) {
+		return nil, ErrQueueEmpty
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Receive(): %w", err)
+	}
+
+	id, err := newNonce()
+	if err != nil {
+		return nil, fmt.Errorf("Receive(): %w", err)
+	}
+
+	m := &Message{
+		ID:       id,
+		Value:    resp.Value,
+		Deadline: time.Now().Add(q.opts.VisibilityTimeout),
+		q:        q,
+	}
+
+	err = q.db.LSet(ctx, q.processing(id), []any{strconv.FormatInt(m.Deadline.UnixMilli(), 10), m.Value})
+	if err == nil {
+		err = push(ctx, q.db, q.name+"_processing", []any{id})
+	}
+	if err != nil {
+		// Not recorded, put it back
+		if insertErr := q.db.LModInsert(ctx, q.name, 0, m.Value); insertErr != nil {
+			return nil, fmt.Errorf("Receive(): %w, and the message was lost putting it back: %v", err, insertErr)
+		}
+		return nil, fmt.Errorf("Receive(): %w", err)
+	}
+
+	return m, nil
+}
+
+// ReceiveWait is Receive, polling until there is an element or ctx is done.
+func (q *Queue) ReceiveWait(ctx context.Context) (*Message, error) {
+	return wait(ctx, q, q.Receive)
+}
+
+// Ack acknowledges the message, it won't be delivered again.
+// Acknowledging a message after its deadline may be too late, it may be requeued already.
+func (m *Message) Ack(ctx context.Context) error {
+	_, err := m.q.db.Del(ctx, []string{m.q.processing(m.ID)})
+	if err != nil {
+		return fmt.Errorf("Ack(): %w", err)
+	}
+
+	return nil
+}
+
+// Nack pushes the message back to the queue at once.
+func (m *Message) Nack(ctx context.Context) error {
+	err := m.q.requeue(ctx, m.ID, m.Value)
+	if err != nil {
+		return fmt.Errorf("Nack(): %w", err)
+	}
+
+	return nil
+}
+
+func (q *Queue) processing(id string) string {
+	return q.name + "_processing:" + id
+}
+
+func (q *Queue) requeue(ctx context.Context, id string, value any) error {
+	err := push(ctx, q.db, q.name, []any{value})
+	if err != nil {
+		return err
+	}
+
+	_, err = q.db.Del(ctx, []string{q.processing(id)})
+	return err
+}
+
+// RequeueExpired pushes the received messages not acknowledged before their deadline back to the queue,
+// returning how many were requeued. Only one RequeueExpired should run at a time per queue.
+func (q *Queue) RequeueExpired(ctx context.Context) (requeued int, err error) {
+	ids, err := q.db.LGet(ctx, q.name+"_processing")
+	if errors.Is(err, protocol.ErrCodeNil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("RequeueExpired(): %w", err)
+	}
+
+	now := time.Now()
+
+	// Receive only appends ids, so removing the handled ones from the end keeps the indices still to visit valid
+	for i := len(ids.Elements) - 1; i >= 0; i-- {
+		var id string
+		switch v := ids.Elements[i].(type) {
+		case string:
+			id = v
+		case []byte:
+			id = string(v)
+		}
+
+		done := false
+		m, err := q.db.LGet(ctx, q.processing(id))
+		switch {
+		case errors.Is(err, protocol.ErrCodeNil):
+			// Acknowledged
+			done = true
+		case err != nil:
+			return requeued, fmt.Errorf("RequeueExpired(): %w", err)
+		case len(m.Elements) != 2:
+			return requeued, fmt.Errorf("RequeueExpired(): invalid processing record of '%s'", id)
+		default:
+			deadline, err := parseDeadline(m.Elements[0])
+			if err != nil {
+				return requeued, fmt.Errorf("RequeueExpired(): %w", err)
+			}
+			if now.After(deadline) {
+				err = q.requeue(ctx, id, m.Elements[1])
+				if err != nil {
+					return requeued, fmt.Errorf("RequeueExpired(): %w", err)
+				}
+				requeued++
+				done = true
+			}
+		}
+
+		if done {
+			err = q.db.LModRemove(ctx, q.name+"_processing", uint64(i))
+			if err != nil {
+				return requeued, fmt.Errorf("RequeueExpired(): %w", err)
+			}
+		}
+	}
+
+	return requeued, nil
+}
+
+func (q *Queue) requeuer() {
+	defer q.wg.Done()
+
+	t := time.NewTicker(q.opts.RequeueInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-q.stop:
+			return
+		case <-t.C:
+		}
+
+		_, err := q.RequeueExpired(context.Background())
+		if err != nil && q.opts.OnRequeueError != nil {
+			q.opts.OnRequeueError(err)
+		}
+	}
+}
+
+// wait calls f until it does not return ErrQueueEmpty, with a delay doubling from MinPoll to MaxPoll.
+func wait[T any](ctx context.Context, q *Queue, f func(ctx context.Context) (T, error)) (T, error) {
+	delay := q.opts.MinPoll
+	for {
+		v, err := f(ctx)
+		if !errors.Is(err, ErrQueueEmpty) {
+			return v, err
+		}
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return v, ctx.Err()
+		case <-t.C:
+		}
+
+		delay *= 2
+		if delay > q.opts.MaxPoll {
+			delay = q.opts.MaxPoll
+		}
+	}
+}
+
+// isEmptyList returns true if err means the list is missing or empty.
+func isEmptyList(err error) bool {
+	var errStr *protocol.ErrorStringResponse
+	return errors.Is(err, protocol.ErrCodeNil) ||
+		errors.As(err, &errStr) && (errStr.Errstr == protocol.ErrStr_ListIsEmpty || errStr.Errstr == protocol.ErrStr_BadListIndex)
+}
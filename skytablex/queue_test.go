@@ -0,0 +1,133 @@
+package skytablex
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQueue(t *testing.T) {
+	ctx := context.Background()
+	q := NewQueue(dial(t, newTestServer(t), "default:lists"), "jobs", QueueOptions{})
+	defer q.Close()
+
+	if _, err := q.Dequeue(ctx); !errors.Is(err, ErrQueueEmpty) {
+		t.Fatalf("Dequeue() error = %v, want %v", err, ErrQueueEmpty)
+	}
+	if n, err := q.Len(ctx); err != nil || n != 0 {
+		t.Fatalf("Len() = %d, %v, want 0", n, err)
+	}
+
+	if err := q.Enqueue(ctx, "a", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Enqueue(ctx, "c"); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := q.Len(ctx); err != nil || n != 3 {
+		t.Fatalf("Len() = %d, %v, want 3", n, err)
+	}
+	if v, err := q.Peek(ctx); err != nil || string(v.([]byte)) != "a" {
+		t.Fatalf("Peek() = %v, %v, want a", v, err)
+	}
+
+	for _, want := range []string{"a", "b", "c"} {
+		v, err := q.Dequeue(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(v.([]byte)) != want {
+			t.Errorf("Dequeue() = %s, want %s", v, want)
+		}
+	}
+
+	if _, err := q.Peek(ctx); !errors.Is(err, ErrQueueEmpty) {
+		t.Errorf("Peek() error = %v, want %v", err, ErrQueueEmpty)
+	}
+
+	q.Close() // Closed again by the deferred call
+}
+
+func TestQueue_DequeueWait(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestServer(t)
+	q := NewQueue(dial(t, srv, "default:lists"), "jobs", QueueOptions{MinPoll: time.Millisecond, MaxPoll: 5 * time.Millisecond})
+	producer := NewQueue(dial(t, srv, "default:lists"), "jobs", QueueOptions{})
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		producer.Enqueue(ctx, "late")
+	}()
+
+	v, err := q.DequeueWait(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(v.([]byte)) != "late" {
+		t.Errorf("DequeueWait() = %s, want late", v)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := q.DequeueWait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("DequeueWait() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestQueue_AtLeastOnce(t *testing.T) {
+	ctx := context.Background()
+	q := NewQueue(dial(t, newTestServer(t), "default:lists"), "jobs", QueueOptions{VisibilityTimeout: 10 * time.Millisecond})
+
+	if err := q.Enqueue(ctx, "acked", "crashed", "nacked"); err != nil {
+		t.Fatal(err)
+	}
+
+	acked, err := q.Receive(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.Receive(ctx); err != nil {
+		t.Fatal(err)
+	}
+	nacked, err := q.Receive(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := acked.Ack(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := nacked.Nack(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Not expired yet
+	if n, err := q.RequeueExpired(ctx); err != nil || n != 0 {
+		t.Fatalf("RequeueExpired() = %d, %v, want 0", n, err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if n, err := q.RequeueExpired(ctx); err != nil || n != 1 {
+		t.Fatalf("RequeueExpired() = %d, %v, want 1", n, err)
+	}
+
+	for _, want := range []string{"nacked", "crashed"} {
+		m, err := q.Receive(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(m.Value.([]byte)) != want {
+			t.Errorf("Receive() = %s, want %s", m.Value, want)
+		}
+		if err := m.Ack(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := q.Receive(ctx); !errors.Is(err, ErrQueueEmpty) {
+		t.Errorf("Receive() error = %v, want %v", err, ErrQueueEmpty)
+	}
+}
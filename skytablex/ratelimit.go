@@ -0,0 +1,250 @@
+package skytablex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/action"
+	"github.com/No3371/go-skytable/protocol"
+)
+
+// ErrRateLimitContention is returned by Allow when the counter kept being updated by other instances.
+var ErrRateLimitContention = errors.New("rate limit: too many concurrent updates")
+
+type RateLimitAlgorithm byte
+
+const (
+	// At most Limit requests per Window, the windows being aligned on the clock.
+	FixedWindow RateLimitAlgorithm = iota
+	// A bucket of Limit tokens, refilled by Limit tokens per Window, a request takes a token.
+	TokenBucket
+)
+
+type RateLimiterOptions struct {
+	Algorithm    RateLimitAlgorithm
+	Limit        int
+	Window       time.Duration
+	Prefix       string        // Prepended to the keys, "ratelimit:" if ""
+	MaxRetries   int           // Attempts when other instances update the same key concurrently, 10 if 0
+	ClaimTimeout time.Duration // A claim older than this, left by a crashed instance, is broken, 1 second if 0
+}
+
+// RateLimiter enforces a limit shared by all the instances using the same Skytable table.
+//
+// The state of each key is stored at Prefix + key as "version|state". As Skytable has no compare-and-swap,
+// an update first claims the next version with SSET on the key + "_v" + version, checks the state
+// is still at the version it read, then writes it with UPDATE. Losers retry with the new state.
+//
+// Time is read from the local clocks, keep the instances in sync.
+type RateLimiter struct {
+	db   skytable.Skytable
+	opts RateLimiterOptions
+}
+
+// NewRateLimiter creates a RateLimiter, an ErrInvalidUsage is returned if Limit or Window is not positive.
+func NewRateLimiter(db skytable.Skytable, opts RateLimiterOptions) (*RateLimiter, error) {
+	if opts.Limit <= 0 {
+		return nil, skytable.NewUsageError(fmt.Sprintf("NewRateLimiter(): Limit must be positive: %d", opts.Limit), nil)
+	}
+	if opts.Window <= 0 {
+		return nil, skytable.NewUsageError(fmt.Sprintf("NewRateLimiter(): Window must be positive: %s", opts.Window), nil)
+	}
+	if opts.Prefix == "" {
+		opts.Prefix = "ratelimit:"
+	}
+	if opts.MaxRetries <= 0 {
+		opts.MaxRetries = 10
+	}
+	if opts.ClaimTimeout <= 0 {
+		opts.ClaimTimeout = time.Second
+	}
+
+	return &RateLimiter{
+		db:   db,
+		opts: opts,
+	}, nil
+}
+
+// Allow takes a request from the limit of the key.
+// If the limit is reached, it returns false and how long until a request would be allowed.
+func (r *RateLimiter) Allow(ctx context.Context, key string) (allowed bool, retryAfter time.Duration, err error) {
+	key = r.opts.Prefix + key
+
+	for attempt := 0; attempt < r.opts.MaxRetries; attempt++ {
+		version, state, err := r.read(ctx, key)
+		if err != nil {
+			return false, 0, fmt.Errorf("Allow(): %w", err)
+		}
+
+		var next string
+		switch r.opts.Algorithm {
+		case TokenBucket:
+			next, allowed, retryAfter = r.takeToken(state, time.Now())
+		default:
+			next, allowed, retryAfter = r.count(state, time.Now())
+		}
+		if !allowed {
+			return false, retryAfter, nil
+		}
+
+		won, err := r.write(ctx, key, version, next)
+		if err != nil {
+			return false, 0, fmt.Errorf("Allow(): %w", err)
+		}
+		if won {
+			return true, 0, nil
+		}
+
+		err = sleepJitter(ctx, attempt)
+		if err != nil {
+			return false, 0, err
+		}
+	}
+
+	return false, 0, fmt.Errorf("Allow(): %w", ErrRateLimitContention)
+}
+
+// count applies the fixed window algorithm, the state being "window start|count".
+func (r *RateLimiter) count(state string, now time.Time) (next string, allowed bool, retryAfter time.Duration) {
+	window := now.Truncate(r.opts.Window)
+
+	var start int64
+	var count int
+	if parts := strings.SplitN(state, "|", 2); len(parts) == 2 {
+		start, _ = strconv.ParseInt(parts[0], 10, 64)
+		count, _ = strconv.Atoi(parts[1])
+	}
+	if start != window.UnixMilli() {
+		count = 0
+	}
+
+	if count >= r.opts.Limit {
+		return "", false, time.Until(window.Add(r.opts.Window))
+	}
+
+	return fmt.Sprintf("%d|%d", window.UnixMilli(), count+1), true, 0
+}
+
+// takeToken applies the token bucket algorithm, the state being "tokens|last refill".
+func (r *RateLimiter) takeToken(state string, now time.Time) (next string, allowed bool, retryAfter time.Duration) {
+	limit := float64(r.opts.Limit)
+	tokens := limit
+	last := now
+	if parts := strings.SplitN(state, "|", 2); len(parts) == 2 {
+		tokens, _ = strconv.ParseFloat(parts[0], 64)
+		ms, _ := strconv.ParseInt(parts[1], 10, 64)
+		last = time.UnixMilli(ms)
+	}
+
+	if elapsed := now.Sub(last); elapsed > 0 {
+		tokens = math.Min(limit, tokens+limit*float64(elapsed)/float64(r.opts.Window))
+	}
+
+	if tokens < 1 {
+		return "", false, time.Duration((1 - tokens) * float64(r.opts.Window) / limit)
+	}
+
+	return fmt.Sprintf("%s|%d", strconv.FormatFloat(tokens-1, 'f', -1, 64), now.UnixMilli()), true, 0
+}
+
+// read returns the version and the state of the key, 0 and "" if it does not exist.
+func (r *RateLimiter) read(ctx context.Context, key string) (version uint64, state string, err error) {
+	v, err := r.db.Get(ctx, key)
+	if errors.Is(err, protocol.ErrCodeNil) {
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", err
+	}
+
+	var s string
+	switch v := v.Value.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	}
+
+	parts := strings.SplitN(s, "|", 2)
+	if len(parts) != 2 {
+		return 0, "", fmt.Errorf("invalid rate limit state: %s", s)
+	}
+	version, err = strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid rate limit state: %s", s)
+	}
+
+	return version, parts[1], nil
+}
+
+// write stores the state as the version after `version`, returning false if another instance did first.
+func (r *RateLimiter) write(ctx context.Context, key string, version uint64, state string) (bool, error) {
+	value := strconv.FormatUint(version+1, 10) + "|" + state
+
+	if version == 0 {
+		err := r.db.SSet(ctx, []action.KVPair{{K: key, V: value}})
+		if errors.Is(err, protocol.ErrCodeOverwriteError) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	claim := key + "_v" + strconv.FormatUint(version+1, 10)
+	err := r.db.SSet(ctx, []action.KVPair{{K: claim, V: strconv.FormatInt(time.Now().UnixMilli(), 10)}})
+	if errors.Is(err, protocol.ErrCodeOverwriteError) {
+		return false, r.breakStaleClaim(ctx, claim)
+	}
+	if err != nil {
+		return false, err
+	}
+	defer r.db.Del(ctx, []string{claim})
+
+	current, _, err := r.read(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	if current != version {
+		return false, nil
+	}
+
+	err = r.db.Update(ctx, key, value)
+	return err == nil, err
+}
+
+// breakStaleClaim deletes the claim if it's older than ClaimTimeout.
+func (r *RateLimiter) breakStaleClaim(ctx context.Context, claim string) error {
+	v, err := r.db.Get(ctx, claim)
+	if errors.Is(err, protocol.ErrCodeNil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	claimed, err := parseDeadline(v.Value)
+	if err != nil || time.Since(claimed) < r.opts.ClaimTimeout {
+		return nil
+	}
+
+	_, err = r.db.Del(ctx, []string{claim})
+	return err
+}
+
+func sleepJitter(ctx context.Context, attempt int) error {
+	t := time.NewTimer(time.Duration(rand.Int63n(int64(time.Millisecond) * int64(attempt+1))))
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
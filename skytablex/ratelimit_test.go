@@ -0,0 +1,126 @@
+package skytablex
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/No3371/go-skytable"
+)
+
+func newTestRateLimiter(t *testing.T, db skytable.Skytable, opts RateLimiterOptions) *RateLimiter {
+	r, err := NewRateLimiter(db, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestNewRateLimiter_Invalid(t *testing.T) {
+	db := dial(t, newTestServer(t), "default:default")
+
+	var errUsage skytable.ErrInvalidUsage
+	for _, opts := range []RateLimiterOptions{
+		{Limit: 0, Window: time.Second},
+		{Limit: 1, Window: 0},
+		{Limit: -1, Window: -time.Second},
+	} {
+		if _, err := NewRateLimiter(db, opts); !errors.As(err, &errUsage) {
+			t.Errorf("NewRateLimiter(%+v) error = %v, want ErrInvalidUsage", opts, err)
+		}
+	}
+}
+
+func TestRateLimiter_FixedWindow(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRateLimiter(t, dial(t, newTestServer(t), "default:default"), RateLimiterOptions{
+		Algorithm: FixedWindow,
+		Limit:     3,
+		Window:    time.Hour,
+	})
+
+	for i := 0; i < 3; i++ {
+		allowed, _, err := r.Allow(ctx, "user")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !allowed {
+			t.Fatalf("Allow() #%d = false, want true", i)
+		}
+	}
+
+	allowed, retryAfter, err := r.Allow(ctx, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if allowed || retryAfter <= 0 || retryAfter > time.Hour {
+		t.Errorf("Allow() = %v, %s, want false and a retry within the hour", allowed, retryAfter)
+	}
+
+	// Keys have their own limits
+	if allowed, _, err := r.Allow(ctx, "other"); err != nil || !allowed {
+		t.Errorf("Allow() = %v, %v, want true", allowed, err)
+	}
+}
+
+func TestRateLimiter_TokenBucket(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRateLimiter(t, dial(t, newTestServer(t), "default:default"), RateLimiterOptions{
+		Algorithm: TokenBucket,
+		Limit:     2,
+		Window:    100 * time.Millisecond,
+	})
+
+	for i := 0; i < 2; i++ {
+		if allowed, _, err := r.Allow(ctx, "user"); err != nil || !allowed {
+			t.Fatalf("Allow() #%d = %v, %v, want true", i, allowed, err)
+		}
+	}
+
+	allowed, retryAfter, err := r.Allow(ctx, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if allowed || retryAfter <= 0 || retryAfter > 50*time.Millisecond {
+		t.Fatalf("Allow() = %v, %s, want false and a retry within 50ms", allowed, retryAfter)
+	}
+
+	time.Sleep(retryAfter + 5*time.Millisecond)
+	if allowed, _, err := r.Allow(ctx, "user"); err != nil || !allowed {
+		t.Errorf("Allow() = %v, %v, want true after the refill", allowed, err)
+	}
+}
+
+func TestRateLimiter_Shared(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestServer(t)
+	opts := RateLimiterOptions{Limit: 5, Window: time.Hour, MaxRetries: 100}
+
+	var allowedCount int32
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		r := newTestRateLimiter(t, dial(t, srv, "default:default"), opts)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 2; j++ {
+				allowed, _, err := r.Allow(ctx, "shared")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if allowed {
+					atomic.AddInt32(&allowedCount, 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowedCount != 5 {
+		t.Errorf("allowed %d requests, want 5", allowedCount)
+	}
+}
@@ -0,0 +1,329 @@
+package skytablex
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/No3371/go-skytable"
+)
+
+type ScanStrategy byte
+
+const (
+	// LSKEYS with the size of the table as the limit, one call per table.
+	ScanSnapshot ScanStrategy = iota
+	// LSKEYS with a limit doubling from PageSize, yielding the keys not seen yet after each call.
+	// The first keys arrive sooner, but keys are transferred multiple times and
+	// keys added or removed while scanning may shift the order and be missed.
+	ScanGrowing
+)
+
+// KeyIndex returns an iterator over the keys of a table, for tables whose keys are also kept in lists.
+type KeyIndex func(ctx context.Context, table string) *ListIterator
+
+type ScanOptions struct {
+	Keyspaces []string // Keyspaces whose tables are scanned, all the keyspaces but "system" if nil
+	Tables    []string // Tables to scan as "keyspace:table", overrides Keyspaces
+	Strategy  ScanStrategy
+	PageSize  uint64 // The first limit of ScanGrowing, 100 if 0
+
+	// Only keys matching the glob are yielded: '*' matches any sequence, '?' any byte, '\' escapes
+	Match string
+	// Only keys for which Filter returns true are yielded
+	Filter func(table, key string) bool
+
+	// If set, the keys are read from the index instead of LSKEYS
+	Index KeyIndex
+}
+
+// KeyScanner enumerates the keys of tables, as LSKEYS has no cursor.
+//
+//	it := skytablex.ScanKeys(ctx, db, skytablex.ScanOptions{Match: "user:*"})
+//	defer it.Close()
+//	for it.Next() {
+//	    table, key := it.Table(), it.Key()
+//	}
+//	if it.Err() != nil { ... }
+type KeyScanner struct {
+	ctx  context.Context
+	db   skytable.Skytable
+	opts ScanOptions
+
+	tables []string
+	ti     int // Index of the next table in tables
+	table  string
+
+	keys []string
+	pos  int
+
+	// ScanGrowing
+	limit uint64
+	seen  map[string]struct{}
+	// Index
+	index *ListIterator
+
+	resolved bool
+	done     bool
+	err      error
+}
+
+// ScanKeys creates a KeyScanner, the tables and the keys are listed lazily.
+func ScanKeys(ctx context.Context, db skytable.Skytable, opts ScanOptions) *KeyScanner {
+	if opts.PageSize == 0 {
+		opts.PageSize = 100
+	}
+
+	return &KeyScanner{
+		ctx:  ctx,
+		db:   db,
+		opts: opts,
+		pos:  -1,
+	}
+}
+
+// Next advances to the next matching key, returning false when all the tables are scanned or an error occurred.
+func (it *KeyScanner) Next() bool {
+	if it.err != nil || it.done {
+		return false
+	}
+
+	if !it.resolved {
+		it.err = it.resolveTables()
+		if it.err != nil {
+			return false
+		}
+		it.resolved = true
+	}
+
+	for {
+		it.pos++
+		for it.pos < len(it.keys) {
+			if it.matches(it.keys[it.pos]) {
+				return true
+			}
+			it.pos++
+		}
+
+		more, err := it.load()
+		if err != nil {
+			it.err = fmt.Errorf("ScanKeys: %s: %w", it.table, err)
+			return false
+		}
+		if !more {
+			it.done = true
+			return false
+		}
+	}
+}
+
+func (it *KeyScanner) matches(key string) bool {
+	if it.opts.Match != "" && !matchGlob(it.opts.Match, key) {
+		return false
+	}
+	if it.opts.Filter != nil && !it.opts.Filter(it.table, key) {
+		return false
+	}
+	return true
+}
+
+func (it *KeyScanner) resolveTables() error {
+	if it.opts.Tables != nil {
+		it.tables = it.opts.Tables
+		return nil
+	}
+
+	keyspaces := it.opts.Keyspaces
+	if keyspaces == nil {
+		var err error
+		keyspaces, err = listKeyspaces(it.ctx, it.db)
+		if err != nil {
+			return fmt.Errorf("ScanKeys: %w", err)
+		}
+	}
+
+	for _, ks := range keyspaces {
+		arr, err := it.db.InspectKeyspace(it.ctx, ks)
+		if err != nil {
+			return fmt.Errorf("ScanKeys: %s: %w", ks, err)
+		}
+		for _, e := range arr.Elements {
+			it.tables = append(it.tables, ks+":"+elementString(e))
+		}
+	}
+
+	return nil
+}
+
+// load replaces it.keys with the next keys, returning false when all the tables are scanned.
+func (it *KeyScanner) load() (more bool, err error) {
+	it.keys, it.pos = it.keys[:0], -1
+
+	if it.index != nil {
+		for it.index.Next() {
+			it.keys = append(it.keys, elementString(it.index.Value()))
+			if uint64(len(it.keys)) >= it.opts.PageSize {
+				return true, nil
+			}
+		}
+		err = it.index.Err()
+		it.index.Close()
+		it.index = nil
+		if err != nil || len(it.keys) > 0 {
+			return err == nil, err
+		}
+	}
+
+	if it.seen != nil {
+		grown, err := it.grow()
+		if err != nil || grown {
+			return grown, err
+		}
+	}
+
+	if it.ti >= len(it.tables) {
+		return false, nil
+	}
+	it.table = it.tables[it.ti]
+	it.ti++
+
+	switch {
+	case it.opts.Index != nil:
+		it.index = it.opts.Index(it.ctx, it.table)
+	case it.opts.Strategy == ScanGrowing:
+		it.seen = make(map[string]struct{})
+		it.limit = 0
+	default:
+		size, err := it.db.DBSize(it.ctx, it.table)
+		if err != nil {
+			return false, err
+		}
+		if size > 0 {
+			arr, err := it.db.LSKeys(it.ctx, it.table, size)
+			if err != nil {
+				return false, err
+			}
+			for _, e := range arr.Elements {
+				it.keys = append(it.keys, elementString(e))
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// grow lists the table with a doubled limit, keeping the unseen keys. It returns false when the table is exhausted.
+func (it *KeyScanner) grow() (bool, error) {
+	if it.limit == 0 {
+		it.limit = it.opts.PageSize
+	} else {
+		it.limit *= 2
+	}
+
+	arr, err := it.db.LSKeys(it.ctx, it.table, it.limit)
+	if err != nil {
+		return false, err
+	}
+
+	for _, e := range arr.Elements {
+		k := elementString(e)
+		if _, seen := it.seen[k]; !seen {
+			it.seen[k] = struct{}{}
+			it.keys = append(it.keys, k)
+		}
+	}
+
+	if uint64(len(arr.Elements)) < it.limit {
+		// The whole table is listed
+		it.seen = nil
+		return len(it.keys) > 0, nil
+	}
+	if len(it.keys) == 0 {
+		// Nothing new this time, try further
+		return it.grow()
+	}
+	return true, nil
+}
+
+// Table returns the table of the current key, as "keyspace:table".
+func (it *KeyScanner) Table() string {
+	return it.table
+}
+
+// Key returns the current key.
+func (it *KeyScanner) Key() string {
+	if it.pos < 0 || it.pos >= len(it.keys) {
+		return ""
+	}
+	return it.keys[it.pos]
+}
+
+// Err returns the error which stopped the scan, if any.
+func (it *KeyScanner) Err() error {
+	return it.err
+}
+
+// Close stops the scan.
+func (it *KeyScanner) Close() {
+	if it.index != nil {
+		it.index.Close()
+		it.index = nil
+	}
+	it.done = true
+	it.keys = nil
+}
+
+func elementString(e any) string {
+	switch e := e.(type) {
+	case string:
+		return e
+	case []byte:
+		return string(e)
+	default:
+		return fmt.Sprint(e)
+	}
+}
+
+// matchGlob reports whether s matches the pattern, where '*' matches any sequence, '?' any byte and '\' escapes.
+func matchGlob(pattern, s string) bool {
+	// Position to resume from when a '*' has to match more
+	starP, starS := -1, 0
+
+	p, i := 0, 0
+	for i < len(s) {
+		if p < len(pattern) {
+			switch c := pattern[p]; c {
+			case '*':
+				starP, starS = p, i
+				p++
+				continue
+			case '?':
+				p++
+				i++
+				continue
+			case '\\':
+				if p+1 < len(pattern) && pattern[p+1] == s[i] {
+					p += 2
+					i++
+					continue
+				}
+			default:
+				if c == s[i] {
+					p++
+					i++
+					continue
+				}
+			}
+		}
+
+		if starP < 0 {
+			return false
+		}
+		starS++
+		p, i = starP+1, starS
+	}
+
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
@@ -0,0 +1,23 @@
+//go:build go1.23
+
+package skytablex
+
+import "iter"
+
+// All returns an iterator over the remaining tables and keys, check Err after the loop:
+//
+//	for table, key := range it.All() {
+//	}
+//	if it.Err() != nil { ... }
+//
+// Breaking out of the loop closes the KeyScanner.
+func (it *KeyScanner) All() iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		for it.Next() {
+			if !yield(it.Table(), it.Key()) {
+				it.Close()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,31 @@
+//go:build go1.23
+
+package skytablex
+
+import (
+	"context"
+	"testing"
+)
+
+func TestKeyScanner_All(t *testing.T) {
+	db := newTestScan(t, 30)
+
+	n := 0
+	it := ScanKeys(context.Background(), db, ScanOptions{Tables: []string{"default:strs"}})
+	for table, key := range it.All() {
+		if table != "default:strs" || key == "" {
+			t.Errorf("All() yielded %s, %s", table, key)
+		}
+		n++
+		if n == 10 {
+			break
+		}
+	}
+
+	if n != 10 || it.Err() != nil {
+		t.Errorf("All() yielded %d keys, %v, want 10", n, it.Err())
+	}
+	if it.Next() {
+		t.Error("Next() = true after breaking out of All(), want false")
+	}
+}
@@ -0,0 +1,133 @@
+package skytablex
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/action"
+)
+
+// newTestScan sets n keys in default:strs and n keys in default:more, returning a client using neither.
+func newTestScan(t *testing.T, n int) *skytable.Conn {
+	ctx := context.Background()
+	srv := newTestServer(t)
+	db := dial(t, srv, "")
+
+	_, err := db.BuildAndExecQuery(skytable.NewQueryPacket([]skytable.Action{
+		action.CreateTable{Path: "default:more", ModelDescription: testTables["default:strs"]},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, table := range []string{"default:strs", "default:more"} {
+		c := dial(t, srv, table)
+		for i := 0; i < n; i++ {
+			if err := c.Set(ctx, fmt.Sprintf("%s-%03d", strings.TrimPrefix(table, "default:"), i), "v"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	return db
+}
+
+func scanAll(t *testing.T, it *KeyScanner) []string {
+	defer it.Close()
+
+	var got []string
+	for it.Next() {
+		got = append(got, it.Table()+"/"+it.Key())
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+
+	sort.Strings(got)
+	return got
+}
+
+func TestScanKeys(t *testing.T) {
+	db := newTestScan(t, 150)
+
+	for _, strategy := range []ScanStrategy{ScanSnapshot, ScanGrowing} {
+		got := scanAll(t, ScanKeys(context.Background(), db, ScanOptions{Strategy: strategy, PageSize: 16}))
+
+		if len(got) != 300 {
+			t.Fatalf("strategy %d: scanned %d keys, want 300", strategy, len(got))
+		}
+		if got[0] != "default:more/more-000" || got[299] != "default:strs/strs-149" {
+			t.Errorf("strategy %d: scanned %s ... %s", strategy, got[0], got[299])
+		}
+		for i := 1; i < len(got); i++ {
+			if got[i] == got[i-1] {
+				t.Errorf("strategy %d: scanned %s twice", strategy, got[i])
+			}
+		}
+	}
+}
+
+func TestScanKeys_Match(t *testing.T) {
+	db := newTestScan(t, 30)
+
+	got := scanAll(t, ScanKeys(context.Background(), db, ScanOptions{
+		Match:  "*-01?",
+		Filter: func(table, key string) bool { return table == "default:strs" },
+	}))
+
+	want := make([]string, 10)
+	for i := range want {
+		want[i] = fmt.Sprintf("default:strs/strs-%03d", 10+i)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("scanned %v, want %v", got, want)
+	}
+}
+
+func TestScanKeys_Index(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestServer(t)
+	lists := dial(t, srv, "default:lists")
+	if err := lists.LSet(ctx, "default:strs", []any{"a", "b", "c"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := scanAll(t, ScanKeys(ctx, dial(t, srv, ""), ScanOptions{
+		Tables:   []string{"default:strs", "default:lists"},
+		PageSize: 2,
+		Index: func(ctx context.Context, table string) *ListIterator {
+			return NewListIterator(ctx, lists, table, ListIteratorOptions{})
+		},
+	}))
+
+	if fmt.Sprint(got) != "[default:strs/a default:strs/b default:strs/c]" {
+		t.Errorf("scanned %v", got)
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	for _, c := range []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"", "", true},
+		{"*", "anything", true},
+		{"user:*", "user:1", true},
+		{"user:*", "users:1", false},
+		{"*:1", "user:1", true},
+		{"a*b*c", "axxbyyc", true},
+		{"a*b*c", "axxbyy", false},
+		{"?", "ab", false},
+		{"a?c", "abc", true},
+		{`a\*`, "a*", true},
+		{`a\*`, "ab", false},
+	} {
+		if got := matchGlob(c.pattern, c.s); got != c.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", c.pattern, c.s, got, c.want)
+		}
+	}
+}
@@ -208,13 +208,13 @@ func (c *ConnX) USetWithSimTTL(ctx context.Context, entries ...action.KVPair) er
 
 	newEntries := make([]action.KVPair, len(entries) * 2)
 	for i, entry := range entries {
-		newEntries[i] = entry
-		newEntries[i + 1] = action.KVPair{ K: entry.K + "_timestamp", V: ts }
+		newEntries[i * 2] = entry
+		newEntries[i * 2 + 1] = action.KVPair{ K: entry.K + "_timestamp", V: ts }
 	}
 
-	c.USet(ctx, newEntries...)
+	_, err := c.USet(ctx, newEntries...)
 
-	return nil
+	return err
 }
 
 // SimTTL only works with BinaryString values
@@ -0,0 +1,433 @@
+package skytablex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/action"
+	"github.com/No3371/go-skytable/protocol"
+)
+
+const StructTag = "sky"
+
+var ErrNotStructPointer = errors.New("expecting a non-nil pointer to a struct")
+
+// ErrMixedStruct is returned for structs with both scalar and list fields when no list table is given, see [StructOptions].
+var ErrMixedStruct = errors.New("struct with both scalar and list fields requires StructOptions.Lists")
+
+type structField struct {
+	name      string
+	index     []int
+	omitempty bool
+	list      bool
+}
+
+// structFields collects the fields tagged with `sky:"name[,omitempty]"`.
+// Fields without the tag or tagged with "-" are ignored.
+func structFields(t reflect.Type) ([]structField, error) {
+	fields := make([]structField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		tag, tagged := f.Tag.Lookup(StructTag)
+		if !tagged || tag == "-" {
+			continue
+		}
+
+		opts := strings.Split(tag, ",")
+		sf := structField{
+			name:  opts[0],
+			index: f.Index,
+		}
+		if sf.name == "" {
+			sf.name = f.Name
+		}
+
+		for _, o := range opts[1:] {
+			switch o {
+			case "omitempty":
+				sf.omitempty = true
+			default:
+				return nil, fmt.Errorf("field %s: unknown tag option: %s", f.Name, o)
+			}
+		}
+
+		if f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() != reflect.Uint8 {
+			sf.list = true
+			if !isScalarKind(f.Type.Elem().Kind()) {
+				return nil, fmt.Errorf("field %s: unsupported list element type: %s", f.Name, f.Type.Elem())
+			}
+		} else if !isScalarKind(f.Type.Kind()) && f.Type.Kind() != reflect.Slice {
+			return nil, fmt.Errorf("field %s: unsupported type: %s", f.Name, f.Type)
+		}
+
+		fields = append(fields, sf)
+	}
+
+	return fields, nil
+}
+
+func isScalarKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func structValue(v any) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, ErrNotStructPointer
+	}
+
+	return rv.Elem(), nil
+}
+
+// encodeScalar converts a field value to a string or []byte, which Skytable stores as str/binstr.
+func encodeScalar(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	case reflect.Slice: // []byte
+		return v.Bytes()
+	default:
+		return nil
+	}
+}
+
+// decodeScalar parses a str/binstr value returned by Skytable into the field.
+func decodeScalar(dst reflect.Value, raw any) error {
+	var s string
+	switch raw := raw.(type) {
+	case string:
+		s = raw
+	case []byte:
+		if dst.Kind() == reflect.Slice {
+			dst.SetBytes(append([]byte(nil), raw...))
+			return nil
+		}
+		s = string(raw)
+	default:
+		return fmt.Errorf("unexpected element: %v (%T)", raw, raw)
+	}
+
+	switch dst.Kind() {
+	case reflect.String:
+		dst.SetString(s)
+	case reflect.Slice: // []byte
+		dst.SetBytes([]byte(s))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		dst.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type: %s", dst.Type())
+	}
+
+	return nil
+}
+
+func encodeList(v reflect.Value) []any {
+	elements := make([]any, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		elements[i] = encodeScalar(v.Index(i))
+	}
+	return elements
+}
+
+func decodeList(dst reflect.Value, arr *protocol.TypedArray) error {
+	list := reflect.MakeSlice(dst.Type(), len(arr.Elements), len(arr.Elements))
+	for i, e := range arr.Elements {
+		if err := decodeScalar(list.Index(i), e); err != nil {
+			return fmt.Errorf("element #%d: %w", i, err)
+		}
+	}
+	dst.Set(list)
+	return nil
+}
+
+// StructOptions configures SaveStructWith and LoadStructWith.
+type StructOptions struct {
+	// Lists stores the `[]T` fields, it must use a list table.
+	// Required for structs with both scalar and list fields, as a table holds either values or lists.
+	Lists skytable.Skytable
+}
+
+// splitStructFields returns the scalar fields and the list fields, rejecting mixed structs if there is no list table.
+func splitStructFields(t reflect.Type, opts StructOptions) (scalars, lists []structField, err error) {
+	fields, err := structFields(t)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, f := range fields {
+		if f.list {
+			lists = append(lists, f)
+		} else {
+			scalars = append(scalars, f)
+		}
+	}
+
+	if len(scalars) > 0 && len(lists) > 0 && opts.Lists == nil {
+		return nil, nil, ErrMixedStruct
+	}
+	return scalars, lists, nil
+}
+
+// buildSaveStructPacket maps the scalar fields of rv into a USET action.
+func buildSaveStructPacket(ctx context.Context, prefix string, rv reflect.Value, scalars []structField) (p *skytable.QueryPacket, entries int) {
+	uset := action.USet{Entries: make([]action.KVPair, 0, len(scalars))}
+	for _, f := range scalars {
+		fv := rv.FieldByIndex(f.index)
+		if f.omitempty && fv.IsZero() {
+			continue
+		}
+
+		uset.Entries = append(uset.Entries, action.KVPair{K: prefix + ":" + f.name, V: encodeScalar(fv)})
+	}
+
+	if len(uset.Entries) == 0 {
+		return nil, 0
+	}
+
+	return skytable.NewQueryPacketContext(ctx, []skytable.Action{uset}), len(uset.Entries)
+}
+
+// SaveStruct stores the fields of the struct pointed by `v` tagged with `sky:"name"` as keys "prefix:name",
+// see [ConnX.SaveStructWith]. Structs with both scalar and list fields are rejected with ErrMixedStruct.
+func (c *ConnX) SaveStruct(ctx context.Context, prefix string, v any) error {
+	return c.SaveStructWith(ctx, prefix, v, StructOptions{})
+}
+
+// SaveStructWith stores the fields of the struct pointed by `v` tagged with `sky:"name"` as keys "prefix:name".
+//
+// Scalar fields are sent in a single USET, overwriting the existing values.
+// `[]T` fields (except []byte) are stored as lists with LSET, in opts.Lists if set, otherwise in the table in use;
+// existing lists are replaced with LMOD CLEAR then LMOD PUSH, which other clients may observe in between.
+// Fields tagged with `sky:"name,omitempty"` are skipped when holding the zero value.
+func (c *ConnX) SaveStructWith(ctx context.Context, prefix string, v any, opts StructOptions) error {
+	rv, err := structValue(v)
+	if err != nil {
+		return fmt.Errorf("SaveStruct(): %w", err)
+	}
+
+	scalars, lists, err := splitStructFields(rv.Type(), opts)
+	if err != nil {
+		return fmt.Errorf("SaveStruct(): %w", err)
+	}
+
+	if p, entries := buildSaveStructPacket(ctx, prefix, rv, scalars); p != nil {
+		rp, err := c.BuildAndExecQuery(p)
+		if err != nil {
+			return err
+		}
+
+		resp := rp.Resps()[0]
+		if resp.Err != nil {
+			return resp.Err
+		}
+
+		switch resp := resp.Value.(type) {
+		case uint64:
+			if resp != uint64(entries) {
+				return protocol.NewUnexpectedProtocolError(fmt.Sprintf("SaveStruct(): Expecting %d entries set, but got: %d", entries, resp), nil)
+			}
+		case protocol.ResponseCode:
+			switch resp {
+			case protocol.RespServerError:
+				return protocol.ErrCodeServerError
+			default:
+				return protocol.NewUnexpectedProtocolError(fmt.Sprintf("SaveStruct(): Unexpected response code: %s", resp), nil)
+			}
+		default:
+			return protocol.NewUnexpectedProtocolError(fmt.Sprintf("SaveStruct(): Unexpected response element: %v", resp), nil)
+		}
+	}
+
+	db := opts.Lists
+	if db == nil {
+		db = &c.Conn
+	}
+	for _, f := range lists {
+		fv := rv.FieldByIndex(f.index)
+		if f.omitempty && fv.IsZero() {
+			continue
+		}
+
+		err = saveList(ctx, db, prefix+":"+f.name, encodeList(fv))
+		if err != nil {
+			return fmt.Errorf("SaveStruct(): field %s: %w", f.name, err)
+		}
+	}
+
+	return nil
+}
+
+// saveList sets the list, replacing its elements if it exists.
+func saveList(ctx context.Context, db skytable.Skytable, key string, elements []any) error {
+	err := db.LSet(ctx, key, elements)
+	if !errors.Is(err, protocol.ErrCodeOverwriteError) {
+		return err
+	}
+
+	err = db.LModClear(ctx, key)
+	if err != nil || len(elements) == 0 {
+		return err
+	}
+	return db.LModPush(ctx, key, elements)
+}
+
+// LoadStruct fills the fields of the struct pointed by `v` tagged with `sky:"name"` from keys "prefix:name",
+// see [ConnX.LoadStructWith]. Structs with both scalar and list fields are rejected with ErrMixedStruct.
+func (c *ConnX) LoadStruct(ctx context.Context, prefix string, v any) error {
+	return c.LoadStructWith(ctx, prefix, v, StructOptions{})
+}
+
+// LoadStructWith fills the fields of the struct pointed by `v` tagged with `sky:"name"` from keys "prefix:name".
+//
+// Scalar fields are fetched with a single MGET, and `[]T` fields (except []byte) are fetched with LGET,
+// from opts.Lists if set, otherwise from the table in use.
+// Fields whose keys do not exist are left untouched.
+func (c *ConnX) LoadStructWith(ctx context.Context, prefix string, v any, opts StructOptions) error {
+	rv, err := structValue(v)
+	if err != nil {
+		return fmt.Errorf("LoadStruct(): %w", err)
+	}
+
+	scalars, lists, err := splitStructFields(rv.Type(), opts)
+	if err != nil {
+		return fmt.Errorf("LoadStruct(): %w", err)
+	}
+
+	if len(scalars) > 0 {
+		mget := action.MGet{Keys: make([]string, 0, len(scalars))}
+		for _, f := range scalars {
+			mget.Keys = append(mget.Keys, prefix+":"+f.name)
+		}
+
+		rp, err := c.BuildAndExecQuery(skytable.NewQueryPacketContext(ctx, []skytable.Action{mget}))
+		if err != nil {
+			return err
+		}
+
+		resp := rp.Resps()[0]
+		if resp.Err != nil {
+			return resp.Err
+		}
+
+		arr, ok := resp.Value.(*protocol.TypedArray)
+		if !ok {
+			return protocol.NewUnexpectedProtocolError(fmt.Sprintf("LoadStruct(): Unexpected response element: %v", resp.Value), nil)
+		}
+		if len(arr.Elements) != len(scalars) {
+			return protocol.NewUnexpectedProtocolError(fmt.Sprintf("LoadStruct(): Expecting %d elements, but got: %d", len(scalars), len(arr.Elements)), nil)
+		}
+
+		for i, f := range scalars {
+			if arr.Elements[i] == nil {
+				continue
+			}
+
+			if err := decodeScalar(rv.FieldByIndex(f.index), arr.Elements[i]); err != nil {
+				return fmt.Errorf("LoadStruct(): field %s: %w", f.name, err)
+			}
+		}
+	}
+
+	db := opts.Lists
+	if db == nil {
+		db = &c.Conn
+	}
+	for _, f := range lists {
+		arr, err := db.LGet(ctx, prefix+":"+f.name)
+		if errors.Is(err, protocol.ErrCodeNil) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("LoadStruct(): field %s: %w", f.name, err)
+		}
+
+		if err := decodeList(rv.FieldByIndex(f.index), arr); err != nil {
+			return fmt.Errorf("LoadStruct(): field %s: %w", f.name, err)
+		}
+	}
+
+	return nil
+}
+
+// See [ConnX.SaveStruct]
+func (c *ConnPoolX) SaveStruct(ctx context.Context, prefix string, v any) error {
+	return c.SaveStructWith(ctx, prefix, v, StructOptions{})
+}
+
+// See [ConnX.SaveStructWith]
+func (c *ConnPoolX) SaveStructWith(ctx context.Context, prefix string, v any, opts StructOptions) error {
+	conn, pusher, err := c.RentConn(false)
+	if err != nil {
+		return fmt.Errorf("*ConnPoolX.SaveStruct(): %w", err)
+	}
+	defer pusher()
+
+	x := ConnX{*conn}
+	return x.SaveStructWith(ctx, prefix, v, opts)
+}
+
+// See [ConnX.LoadStruct]
+func (c *ConnPoolX) LoadStruct(ctx context.Context, prefix string, v any) error {
+	return c.LoadStructWith(ctx, prefix, v, StructOptions{})
+}
+
+// See [ConnX.LoadStructWith]
+func (c *ConnPoolX) LoadStructWith(ctx context.Context, prefix string, v any, opts StructOptions) error {
+	conn, pusher, err := c.RentConn(false)
+	if err != nil {
+		return fmt.Errorf("*ConnPoolX.LoadStruct(): %w", err)
+	}
+	defer pusher()
+
+	x := ConnX{*conn}
+	return x.LoadStructWith(ctx, prefix, v, opts)
+}
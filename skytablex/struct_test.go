@@ -0,0 +1,111 @@
+package skytablex
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testProfile struct {
+	Name    string   `sky:"name"`
+	Age     int      `sky:"age"`
+	Score   float64  `sky:"score,omitempty"`
+	Avatar  []byte   `sky:"avatar,omitempty"`
+	Tags    []string `sky:"tags"`
+	Ignored string
+}
+
+func TestStructFields(t *testing.T) {
+	fields, err := structFields(reflect.TypeOf(testProfile{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []structField{
+		{"name", []int{0}, false, false},
+		{"age", []int{1}, false, false},
+		{"score", []int{2}, true, false},
+		{"avatar", []int{3}, true, false},
+		{"tags", []int{4}, false, true},
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("structFields() = %v, want %v", fields, want)
+	}
+
+	_, err = structFields(reflect.TypeOf(struct {
+		M map[string]string `sky:"m"`
+	}{}))
+	if err == nil {
+		t.Error("structFields() expected an error for unsupported type")
+	}
+}
+
+func TestStructRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestServer(t)
+	cx := &ConnX{*dial(t, srv, "default:strs")}
+	lists := dial(t, srv, "default:lists")
+	opts := StructOptions{Lists: lists}
+
+	src := testProfile{Name: "alice", Age: -7, Avatar: []byte("png"), Tags: []string{"a", "b"}}
+	if err := cx.SaveStructWith(ctx, "user:1", &src, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var dst testProfile
+	if err := cx.LoadStructWith(ctx, "user:1", &dst, opts); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(src, dst) {
+		t.Errorf("round trip = %+v, want %+v", dst, src)
+	}
+
+	// Saved again over the existing keys and list
+	src = testProfile{Name: "bob", Age: 3, Score: 1.5, Tags: []string{"c"}}
+	if err := cx.SaveStructWith(ctx, "user:1", &src, opts); err != nil {
+		t.Fatal(err)
+	}
+	dst = testProfile{}
+	if err := cx.LoadStructWith(ctx, "user:1", &dst, opts); err != nil {
+		t.Fatal(err)
+	}
+	src.Avatar = []byte("png") // omitempty, the previous value is kept
+	if !reflect.DeepEqual(src, dst) {
+		t.Errorf("overwrite = %+v, want %+v", dst, src)
+	}
+
+	// Missing keys leave the fields untouched
+	dst = testProfile{Name: "unchanged", Tags: []string{"x"}}
+	if err := cx.LoadStructWith(ctx, "user:2", &dst, opts); err != nil {
+		t.Fatal(err)
+	}
+	if dst.Name != "unchanged" || dst.Age != 0 || !reflect.DeepEqual(dst.Tags, []string{"x"}) {
+		t.Errorf("missing keys = %+v, want the fields untouched", dst)
+	}
+
+	if err := cx.SaveStruct(ctx, "user:3", &src); !errors.Is(err, ErrMixedStruct) {
+		t.Errorf("SaveStruct() error = %v, want %v", err, ErrMixedStruct)
+	}
+	if err := cx.LoadStruct(ctx, "user:1", &dst); !errors.Is(err, ErrMixedStruct) {
+		t.Errorf("LoadStruct() error = %v, want %v", err, ErrMixedStruct)
+	}
+	if err := cx.SaveStruct(ctx, "x", src); !errors.Is(err, ErrNotStructPointer) {
+		t.Errorf("SaveStruct() error = %v, want %v", err, ErrNotStructPointer)
+	}
+
+	// Scalar-only structs need no list table
+	type counter struct {
+		N int `sky:"n"`
+	}
+	if err := cx.SaveStruct(ctx, "c", &counter{N: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cx.SaveStruct(ctx, "c", &counter{N: 2}); err != nil {
+		t.Fatal(err)
+	}
+	var n counter
+	if err := cx.LoadStruct(ctx, "c", &n); err != nil || n.N != 2 {
+		t.Errorf("LoadStruct() = %+v, %v, want N = 2", n, err)
+	}
+}
@@ -0,0 +1,305 @@
+package skytablex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/action"
+	"github.com/No3371/go-skytable/protocol"
+	"github.com/No3371/go-skytable/response"
+)
+
+type ExpiryOptions struct {
+	Suffix        string        // Appended to a key to get the key of its deadline, "_expiry" if ""
+	IndexKey      string        // The list of the expiry index, "skytablex_expiry" if ""
+	SweepInterval time.Duration // How often the sweeper runs, no sweeper if 0
+	OnSweepError  func(error)   // Called when a sweep fails, ignored if nil
+}
+
+// Expiry emulates expiring keys, which Skytable does not support.
+//
+// SetEx stores the deadline of a key next to it, at key + Suffix, as a decimal UNIX timestamp in milliseconds
+// so both str and binstr tables can hold it. Get, GetString and GetBytes treat expired keys as missing
+// and delete them. Keys set without SetEx never expire.
+//
+// If an index is provided, SetEx also pushes "deadline|key" to the IndexKey list of the index,
+// which must be a table with a list<str> or list<binstr> value type, so the sweeper can delete the
+// expired keys nobody reads. Only run one sweeper per index, and as it runs in background,
+// use clients safe for concurrent use like [skytable.ConnPool].
+type Expiry struct {
+	data  skytable.Skytable
+	index skytable.Skytable
+	opts  ExpiryOptions
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewExpiry creates an Expiry over `data`, the table of the values, and `index`, the table of the expiry index.
+// `index` can be nil, expired keys are then only deleted when read.
+//
+// The sweeper starts if opts.SweepInterval is set and an index is provided, stop it with Close.
+func NewExpiry(data, index skytable.Skytable, opts ExpiryOptions) *Expiry {
+	if opts.Suffix == "" {
+		opts.Suffix = "_expiry"
+	}
+	if opts.IndexKey == "" {
+		opts.IndexKey = "skytablex_expiry"
+	}
+
+	e := &Expiry{
+		data:  data,
+		index: index,
+		opts:  opts,
+		stop:  make(chan struct{}),
+	}
+
+	if index != nil && opts.SweepInterval > 0 {
+		e.wg.Add(1)
+		go e.sweeper()
+	}
+
+	return e
+}
+
+// Close stops the sweeper. It can be called more than once.
+func (e *Expiry) Close() {
+	e.stopOnce.Do(func() { close(e.stop) })
+	e.wg.Wait()
+}
+
+// SetEx sets the key to the value, overwriting it, and makes it expire after ttl.
+func (e *Expiry) SetEx(ctx context.Context, key string, value any, ttl time.Duration) error {
+	deadline := time.Now().Add(ttl).UnixMilli()
+
+	_, err := e.data.USet(ctx,
+		action.KVPair{K: key, V: value},
+		action.KVPair{K: key + e.opts.Suffix, V: strconv.FormatInt(deadline, 10)},
+	)
+	if err != nil {
+		return fmt.Errorf("SetEx(): %w", err)
+	}
+
+	if e.index != nil {
+		err = push(ctx, e.index, e.opts.IndexKey, []any{strconv.FormatInt(deadline, 10) + "|" + key})
+		if err != nil {
+			return fmt.Errorf("SetEx(): failed to index '%s': %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// Get returns the value of the key, or protocol.ErrCodeNil if it's missing or expired.
+func (e *Expiry) Get(ctx context.Context, key string) (response.ResponseEntry, error) {
+	value, _, err := e.get(ctx, key)
+	if err != nil {
+		return response.EmptyResponseEntry, err
+	}
+
+	return response.ResponseEntry{Value: value}, nil
+}
+
+// a strict version of [Get] that only success if the value is stored as String in Skytable.
+func (e *Expiry) GetString(ctx context.Context, key string) (string, error) {
+	value, _, err := e.get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	default:
+		return "", protocol.ErrWrongDataType
+	}
+}
+
+// a strict version of [Get] that only success if the value is stored as BinaryString in Skytable.
+func (e *Expiry) GetBytes(ctx context.Context, key string) ([]byte, error) {
+	value, _, err := e.get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	default:
+		return nil, protocol.ErrWrongDataType
+	}
+}
+
+// TTL returns how long until the key expires, or 0 if it never expires.
+func (e *Expiry) TTL(ctx context.Context, key string) (time.Duration, error) {
+	arr, err := e.data.MGet(ctx, []string{key, key + e.opts.Suffix})
+	if err != nil {
+		return 0, fmt.Errorf("TTL(): %w", err)
+	}
+	if len(arr.Elements) != 2 || arr.Elements[0] == nil {
+		return 0, protocol.ErrCodeNil
+	}
+	if arr.Elements[1] == nil {
+		return 0, nil
+	}
+
+	deadline, err := parseDeadline(arr.Elements[1])
+	if err != nil {
+		return 0, err
+	}
+
+	ttl := time.Until(deadline)
+	if ttl <= 0 {
+		return 0, protocol.ErrCodeNil
+	}
+	return ttl, nil
+}
+
+// Del deletes the keys and their deadlines. Their index entries are dropped by the sweeper.
+func (e *Expiry) Del(ctx context.Context, keys []string) (deleted uint64, err error) {
+	deadlines := make([]string, len(keys))
+	for i, k := range keys {
+		deadlines[i] = k + e.opts.Suffix
+	}
+
+	deleted, err = e.data.Del(ctx, keys)
+	if err != nil {
+		return deleted, fmt.Errorf("Del(): %w", err)
+	}
+
+	_, err = e.data.Del(ctx, deadlines)
+	if err != nil {
+		return deleted, fmt.Errorf("Del(): %w", err)
+	}
+	return deleted, nil
+}
+
+// get reads the key and its deadline at once, deleting the key if it's expired.
+func (e *Expiry) get(ctx context.Context, key string) (value any, expired bool, err error) {
+	arr, err := e.data.MGet(ctx, []string{key, key + e.opts.Suffix})
+	if err != nil {
+		return nil, false, err
+	}
+	if len(arr.Elements) != 2 {
+		return nil, false, protocol.NewUnexpectedProtocolError(fmt.Sprintf("Get(): Unexpected response length: %d", len(arr.Elements)), nil)
+	}
+	if arr.Elements[0] == nil {
+		return nil, false, protocol.ErrCodeNil
+	}
+	if arr.Elements[1] == nil {
+		return arr.Elements[0], false, nil
+	}
+
+	deadline, err := parseDeadline(arr.Elements[1])
+	if err != nil {
+		return nil, false, err
+	}
+
+	if time.Now().Before(deadline) {
+		return arr.Elements[0], false, nil
+	}
+
+	// The key may be set again between the read and the delete, then it's lost
+	_, err = e.data.Del(ctx, []string{key, key + e.opts.Suffix})
+	if err != nil {
+		return nil, false, err
+	}
+	return nil, true, protocol.ErrCodeNil
+}
+
+func parseDeadline(v any) (time.Time, error) {
+	var s string
+	switch v := v.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	}
+
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid expiry deadline: %v", v)
+	}
+	return time.UnixMilli(ms), nil
+}
+
+// Sweep deletes the expired keys of the index, returning how many were deleted.
+func (e *Expiry) Sweep(ctx context.Context) (deleted int, err error) {
+	if e.index == nil {
+		return 0, skytable.NewUsageError("Sweep(): no index", nil)
+	}
+
+	arr, err := e.index.LGet(ctx, e.opts.IndexKey)
+	if errors.Is(err, protocol.ErrCodeNil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("Sweep(): %w", err)
+	}
+
+	now := time.Now()
+
+	// SetEx only appends to the index, so sweeping from the end keeps the indices of the entries before valid
+	for i := len(arr.Elements) - 1; i >= 0; i-- {
+		var entry string
+		switch v := arr.Elements[i].(type) {
+		case string:
+			entry = v
+		case []byte:
+			entry = string(v)
+		}
+
+		sep := strings.IndexByte(entry, '|')
+		if sep < 0 {
+			continue
+		}
+		deadline, err := parseDeadline(entry[:sep])
+		if err != nil || now.Before(deadline) {
+			continue
+		}
+		key := entry[sep+1:]
+
+		// The key may have been set again with a later deadline
+		_, expired, err := e.get(ctx, key)
+		if err != nil && !errors.Is(err, protocol.ErrCodeNil) {
+			return deleted, fmt.Errorf("Sweep(): %w", err)
+		}
+		if expired {
+			deleted++
+		}
+
+		err = e.index.LModRemove(ctx, e.opts.IndexKey, uint64(i))
+		if err != nil {
+			return deleted, fmt.Errorf("Sweep(): %w", err)
+		}
+	}
+
+	return deleted, nil
+}
+
+func (e *Expiry) sweeper() {
+	defer e.wg.Done()
+
+	t := time.NewTicker(e.opts.SweepInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-e.stop:
+			return
+		case <-t.C:
+		}
+
+		_, err := e.Sweep(context.Background())
+		if err != nil && e.opts.OnSweepError != nil {
+			e.opts.OnSweepError(err)
+		}
+	}
+}
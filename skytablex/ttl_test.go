@@ -0,0 +1,177 @@
+package skytablex
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/No3371/go-skytable"
+	"github.com/No3371/go-skytable/action"
+	"github.com/No3371/go-skytable/protocol"
+	"github.com/No3371/go-skytable/skytabletest"
+)
+
+var testTables = map[string]protocol.KeyMapDescription{
+	"default:strs":  {KeyType: protocol.DDLDataTypes_String, ValueType: protocol.DDLDataTypes_String},
+	"default:lists": {KeyType: protocol.DDLDataTypes_String, ValueType: protocol.DDLDataTypes_List},
+}
+
+// newTestServer starts a fake server with a keymap(str,str) table and a keymap(str,list) table.
+func newTestServer(t *testing.T) *skytabletest.Server {
+	srv, err := skytabletest.NewServer(skytabletest.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { srv.Close() })
+
+	c := dial(t, srv, "")
+	for path, desc := range testTables {
+		_, err = c.BuildAndExecQuery(skytable.NewQueryPacket([]skytable.Action{
+			action.CreateTable{Path: path, ModelDescription: desc},
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return srv
+}
+
+func dial(t *testing.T, srv *skytabletest.Server, path string) *skytable.Conn {
+	c, err := skytable.NewConn(srv.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	if path != "" {
+		if err := c.Use(context.Background(), path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return c
+}
+
+func TestExpiry(t *testing.T) {
+	ctx := context.Background()
+	data := dial(t, newTestServer(t), "default:strs")
+	e := NewExpiry(data, nil, ExpiryOptions{})
+	defer e.Close()
+
+	if err := e.SetEx(ctx, "short", "v", 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.SetEx(ctx, "long", "v", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := data.Set(ctx, "forever", "v"); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, err := e.GetString(ctx, "short"); err != nil || v != "v" {
+		t.Fatalf("GetString() = %s, %v", v, err)
+	}
+	if ttl, err := e.TTL(ctx, "long"); err != nil || ttl <= 59*time.Minute {
+		t.Errorf("TTL() = %s, %v", ttl, err)
+	}
+	if ttl, err := e.TTL(ctx, "forever"); err != nil || ttl != 0 {
+		t.Errorf("TTL() = %s, %v, want 0", ttl, err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if _, err := e.GetString(ctx, "short"); !errors.Is(err, protocol.ErrCodeNil) {
+		t.Fatalf("GetString() error = %v, want %v", err, protocol.ErrCodeNil)
+	}
+	// Lazily deleted
+	if n, err := data.Exists(ctx, []string{"short", "short_expiry"}); err != nil || n != 0 {
+		t.Errorf("Exists() = %d, %v, want 0", n, err)
+	}
+	if _, err := e.GetString(ctx, "long"); err != nil {
+		t.Error(err)
+	}
+	if _, err := e.GetString(ctx, "forever"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestExpiry_Binary(t *testing.T) {
+	ctx := context.Background()
+	e := NewExpiry(dial(t, newTestServer(t), "default:default"), nil, ExpiryOptions{})
+	defer e.Close()
+
+	if err := e.SetEx(ctx, "k", []byte{0, 0xff}, 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := e.GetBytes(ctx, "k"); err != nil || string(v) != "\x00\xff" {
+		t.Fatalf("GetBytes() = %v, %v", v, err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if _, err := e.GetBytes(ctx, "k"); !errors.Is(err, protocol.ErrCodeNil) {
+		t.Fatalf("GetBytes() error = %v, want %v", err, protocol.ErrCodeNil)
+	}
+}
+
+func TestExpiry_Sweep(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestServer(t)
+	data, lists := dial(t, srv, "default:strs"), dial(t, srv, "default:lists")
+	e := NewExpiry(data, lists, ExpiryOptions{})
+	defer e.Close()
+
+	for _, k := range []string{"a", "b", "c"} {
+		if err := e.SetEx(ctx, k, "v", 10*time.Millisecond); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := e.SetEx(ctx, "d", "v", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	// Set again with a later deadline, the old index entry must not delete it
+	if err := e.SetEx(ctx, "c", "v", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	deleted, err := e.Sweep(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 2 {
+		t.Errorf("Sweep() = %d, want 2", deleted)
+	}
+
+	if n, err := data.Exists(ctx, []string{"a", "b", "c", "d"}); err != nil || n != 2 {
+		t.Errorf("Exists() = %d, %v, want 2", n, err)
+	}
+	if n, err := lists.LGetLen(ctx, "skytablex_expiry"); err != nil || n != 2 {
+		t.Errorf("LGetLen() = %d, %v, want 2", n, err)
+	}
+}
+
+func TestExpiry_Sweeper(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestServer(t)
+	e := NewExpiry(dial(t, srv, "default:strs"), dial(t, srv, "default:lists"), ExpiryOptions{SweepInterval: 10 * time.Millisecond})
+	defer e.Close()
+
+	// The sweeper has its own conns
+	setter := NewExpiry(dial(t, srv, "default:strs"), dial(t, srv, "default:lists"), ExpiryOptions{})
+	if err := setter.SetEx(ctx, "k", "v", 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if n, err := setter.data.Exists(ctx, []string{"k"}); err == nil && n == 0 {
+			e.Close() // Closed again by the deferred call
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("expecting the sweeper to delete the key")
+}